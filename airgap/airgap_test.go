@@ -0,0 +1,82 @@
+package airgap
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestFragments(t *testing.T) {
+	payload := bytes.Repeat([]byte("epik message "), 100)
+	envelope, err := Encode(KindEpiKMessage, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fragments := []string{}
+	if err := json.Unmarshal([]byte(Split(envelope, 100)), &fragments); err != nil {
+		t.Fatal(err)
+	}
+	if len(fragments) < 2 {
+		t.Fatalf("envelope not split: %d fragments", len(fragments))
+	}
+
+	// any order, with repeats as in an animated QR
+	d := NewDecoder()
+	for i := len(fragments) - 1; i >= 0; i-- {
+		complete, err := d.Receive(fragments[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if complete != (i == 0) {
+			t.Fatalf("fragment %d: complete %v", i, complete)
+		}
+		if i == len(fragments)-1 {
+			if _, err := d.Receive(fragments[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	result, err := d.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload2, err := DecodeKind(result, KindEpiKMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(payload, payload2) {
+		t.Fatal("payload changed")
+	}
+
+	other, _ := Encode(KindETHTransaction, []byte("other"))
+	otherFragments := []string{}
+	_ = json.Unmarshal([]byte(Split(other+other+other, 8)), &otherFragments)
+	d.Reset()
+	if _, err := d.Receive(fragments[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Receive(otherFragments[0]); err == nil {
+		t.Fatal("fragment of another envelope accepted")
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	envelope, err := Encode(KindETHSignedTransaction, []byte{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDecoder()
+	complete, err := d.Receive(envelope)
+	if err != nil || !complete {
+		t.Fatalf("single envelope: %v %v", complete, err)
+	}
+	if _, err := DecodeKind(envelope, KindEpiKMessage); err == nil {
+		t.Fatal("wrong kind accepted")
+	}
+	corrupted := []byte(envelope)
+	corrupted[3] ^= 1
+	if _, err := Decode(string(corrupted)); err == nil {
+		t.Fatal("corrupted envelope accepted")
+	}
+}
@@ -0,0 +1,82 @@
+package airgap
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"strings"
+)
+
+// Version of the envelope layout
+const Version = 1
+
+// payload kinds, signatures only travel inside the signed kinds
+const (
+	// KindEpiKMessage unsigned EpiK message, CBOR
+	KindEpiKMessage = 1
+	// KindEpiKSignedMessage signed EpiK message, CBOR
+	KindEpiKSignedMessage = 2
+	// KindETHTransaction unsigned Ethereum transaction, RLP of the EIP-155 signing payload
+	KindETHTransaction = 3
+	// KindETHSignedTransaction signed Ethereum transaction, RLP
+	KindETHSignedTransaction = 4
+)
+
+// upper case base32 fits the QR alphanumeric mode
+var envelopeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+//Envelope ...
+type Envelope struct {
+	Version int
+	Kind    int
+	Payload []byte
+}
+
+//Encode envelope text: base32 of version, kind, payload and a crc32 of them
+func Encode(kind int, payload []byte) (envelope string, err error) {
+	if kind <= 0 || kind > 0xff {
+		return "", fmt.Errorf("invalid payload kind: %d", kind)
+	}
+	data := make([]byte, 0, len(payload)+6)
+	data = append(data, Version, byte(kind))
+	data = append(data, payload...)
+	data = append(data, make([]byte, 4)...)
+	binary.BigEndian.PutUint32(data[len(data)-4:], crc32.ChecksumIEEE(data[:len(data)-4]))
+	return envelopeEncoding.EncodeToString(data), nil
+}
+
+//Decode envelope text made by Encode
+func Decode(envelope string) (e *Envelope, err error) {
+	data, err := envelopeEncoding.DecodeString(strings.ToUpper(strings.TrimSpace(envelope)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid envelope: %w", err)
+	}
+	if len(data) < 6 {
+		return nil, fmt.Errorf("invalid envelope: too short")
+	}
+	body, sum := data[:len(data)-4], data[len(data)-4:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(sum) {
+		return nil, fmt.Errorf("invalid envelope: checksum mismatch")
+	}
+	if body[0] != Version {
+		return nil, fmt.Errorf("envelope version not support: %d", body[0])
+	}
+	return &Envelope{
+		Version: int(body[0]),
+		Kind:    int(body[1]),
+		Payload: body[2:],
+	}, nil
+}
+
+//DecodeKind Decode, failing unless the payload is of kind
+func DecodeKind(envelope string, kind int) (payload []byte, err error) {
+	e, err := Decode(envelope)
+	if err != nil {
+		return nil, err
+	}
+	if e.Kind != kind {
+		return nil, fmt.Errorf("unexpected payload kind %d, want %d", e.Kind, kind)
+	}
+	return e.Payload, nil
+}
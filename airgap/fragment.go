@@ -0,0 +1,157 @@
+package airgap
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Multi-part QR codes: an envelope too large for one code is cut into
+// fragments "EPKUR/<index>-<total>/<crc32 of the envelope>/<part>" that an
+// animated QR shows in a loop. The receiver collects them in any order.
+
+// FragmentPrefix starts every fragment
+const FragmentPrefix = "EPKUR/"
+
+// DefaultFragmentLen keeps each QR code small enough to scan from a phone screen
+const DefaultFragmentLen = 200
+
+//Split envelope into a JSON list of fragments of at most maxLen envelope characters,
+//an envelope that fits is returned as the only element unchanged
+func Split(envelope string, maxLen int) (fragmentsJSON string) {
+	if maxLen <= 0 {
+		maxLen = DefaultFragmentLen
+	}
+	fragments := []string{}
+	if len(envelope) <= maxLen {
+		fragments = append(fragments, envelope)
+	} else {
+		total := (len(envelope) + maxLen - 1) / maxLen
+		sum := checksum(envelope)
+		for i := 0; i < total; i++ {
+			end := (i + 1) * maxLen
+			if end > len(envelope) {
+				end = len(envelope)
+			}
+			fragments = append(fragments, fmt.Sprintf("%s%d-%d/%s/%s", FragmentPrefix, i+1, total, sum, envelope[i*maxLen:end]))
+		}
+	}
+	data, _ := json.Marshal(fragments)
+	return string(data)
+}
+
+//Decoder reassembles the fragments of one envelope
+type Decoder struct {
+	total    int
+	checksum string
+	parts    map[int]string
+	envelope string
+	lk       sync.Mutex
+}
+
+//NewDecoder ...
+func NewDecoder() *Decoder {
+	return &Decoder{parts: map[int]string{}}
+}
+
+//Receive adds a scanned fragment (or a whole envelope), complete is true once Result is available.
+//Fragments of another envelope are rejected, call Reset to start over.
+func (d *Decoder) Receive(fragment string) (complete bool, err error) {
+	d.lk.Lock()
+	defer d.lk.Unlock()
+
+	fragment = strings.TrimSpace(fragment)
+	if !strings.HasPrefix(strings.ToUpper(fragment), FragmentPrefix) {
+		if _, err := Decode(fragment); err != nil {
+			return false, err
+		}
+		d.envelope = fragment
+		return true, nil
+	}
+
+	fields := strings.SplitN(fragment[len(FragmentPrefix):], "/", 3)
+	if len(fields) != 3 {
+		return false, fmt.Errorf("invalid fragment")
+	}
+	seq := strings.SplitN(fields[0], "-", 2)
+	if len(seq) != 2 {
+		return false, fmt.Errorf("invalid fragment sequence: %s", fields[0])
+	}
+	index, err := strconv.Atoi(seq[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid fragment sequence: %s", fields[0])
+	}
+	total, err := strconv.Atoi(seq[1])
+	if err != nil || total <= 0 || index <= 0 || index > total {
+		return false, fmt.Errorf("invalid fragment sequence: %s", fields[0])
+	}
+	sum := strings.ToUpper(fields[1])
+
+	if d.total == 0 {
+		d.total = total
+		d.checksum = sum
+	} else if d.total != total || d.checksum != sum {
+		return false, fmt.Errorf("fragment belongs to another envelope")
+	}
+	d.parts[index] = fields[2]
+	if len(d.parts) < d.total {
+		return false, nil
+	}
+
+	var sb strings.Builder
+	for i := 1; i <= d.total; i++ {
+		sb.WriteString(d.parts[i])
+	}
+	envelope := sb.String()
+	if checksum(envelope) != d.checksum {
+		return false, fmt.Errorf("reassembled envelope checksum mismatch")
+	}
+	if _, err := Decode(envelope); err != nil {
+		return false, err
+	}
+	d.envelope = envelope
+	return true, nil
+}
+
+//Progress share of fragments received, 0 to 1
+func (d *Decoder) Progress() float64 {
+	d.lk.Lock()
+	defer d.lk.Unlock()
+
+	if d.envelope != "" {
+		return 1
+	}
+	if d.total == 0 {
+		return 0
+	}
+	return float64(len(d.parts)) / float64(d.total)
+}
+
+//Result the reassembled envelope
+func (d *Decoder) Result() (envelope string, err error) {
+	d.lk.Lock()
+	defer d.lk.Unlock()
+
+	if d.envelope == "" {
+		return "", fmt.Errorf("envelope incomplete")
+	}
+	return d.envelope, nil
+}
+
+//Reset forgets all fragments
+func (d *Decoder) Reset() {
+	d.lk.Lock()
+	defer d.lk.Unlock()
+
+	d.total = 0
+	d.checksum = ""
+	d.parts = map[int]string{}
+	d.envelope = ""
+}
+
+func checksum(envelope string) string {
+	return fmt.Sprintf("%08X", crc32.ChecksumIEEE([]byte(envelope)))
+}
@@ -0,0 +1,270 @@
+package epik
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/EpiK-Protocol/go-epik/api"
+	"github.com/EpiK-Protocol/go-epik/chain/actors"
+	"github.com/EpiK-Protocol/go-epik/chain/actors/builtin/expert"
+	"github.com/EpiK-Protocol/go-epik/chain/actors/builtin/miner"
+	"github.com/EpiK-Protocol/go-epik/chain/actors/builtin/retrieval"
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/expertfund"
+	fminer "github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/vesting"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/vote"
+)
+
+// actor kinds of the method registry, named like the builtin actor codes
+const (
+	ActorAccount    = "account"
+	ActorMultisig   = "multisig"
+	ActorMiner      = "storageminer"
+	ActorExpert     = "expert"
+	ActorExpertFund = "expertfund"
+	ActorVote       = "vote"
+	ActorRetrieval  = "retrieval"
+	ActorVesting    = "vesting"
+)
+
+// MethodSend is accepted by every actor
+const MethodSend = "Send"
+
+type cborParams interface {
+	MarshalCBOR(io.Writer) error
+	UnmarshalCBOR(io.Reader) error
+}
+
+//ActorMethod a registered actor method, Params is a JSON template of its parameters.
+//Amounts lists the fields of Params given in EPK, it is [""] when Params itself is an amount.
+type ActorMethod struct {
+	Actor   string          `json:"actor"`
+	Name    string          `json:"name"`
+	Num     abi.MethodNum   `json:"num"`
+	Params  json.RawMessage `json:"params"`
+	Amounts []string        `json:"amounts,omitempty"`
+
+	params func() cborParams
+}
+
+// actor kind -> lower case method name -> method
+var actorMethods = map[string]map[string]*ActorMethod{}
+
+// registerMethod amounts are the token amount fields of params, other big
+// integers like storage power are not scaled to EPK
+func registerMethod(actor string, name string, num abi.MethodNum, params func() cborParams, amounts ...string) {
+	if actorMethods[actor] == nil {
+		actorMethods[actor] = map[string]*ActorMethod{}
+	}
+	m := &ActorMethod{
+		Actor:   actor,
+		Name:    name,
+		Num:     num,
+		Params:  json.RawMessage("null"),
+		Amounts: amounts,
+		params:  params,
+	}
+	if params != nil {
+		p := params()
+		m.Params, _ = json.Marshal(p)
+		checkAmounts(m, p)
+	}
+	actorMethods[actor][strings.ToLower(name)] = m
+}
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// checkAmounts panics when an amount field of m is not a big integer of p,
+// formatParams relies on it
+func checkAmounts(m *ActorMethod, p cborParams) {
+	t := reflect.TypeOf(p).Elem()
+	for _, name := range m.Amounts {
+		if name == "" && t == bigIntType && len(m.Amounts) == 1 {
+			continue
+		}
+		if f, ok := t.FieldByName(name); ok && t.Kind() == reflect.Struct && f.Type == bigIntType {
+			continue
+		}
+		panic(fmt.Sprintf("%s.%s: %q is not an amount field", m.Actor, m.Name, name))
+	}
+}
+
+func addressParams() cborParams { return &address.Address{} }
+
+func amountParams() cborParams { return &abi.TokenAmount{} }
+
+func init() {
+	registerMethod(ActorVote, "Vote", builtin.MethodsVote.Vote, addressParams)
+	registerMethod(ActorVote, "Rescind", builtin.MethodsVote.Rescind, func() cborParams { return &vote.RescindParams{} }, "Votes")
+	registerMethod(ActorVote, "Withdraw", builtin.MethodsVote.Withdraw, addressParams)
+
+	registerMethod(ActorExpertFund, "ApplyForExpert", builtin.MethodsExpertFunds.ApplyForExpert, func() cborParams { return &expertfund.ApplyForExpertParams{} })
+	registerMethod(ActorExpert, "Nominate", expert.Methods.Nominate, addressParams)
+
+	registerMethod(ActorMiner, "AddPledge", miner.Methods.AddPledge, nil)
+	registerMethod(ActorMiner, "ApplyForWithdraw", miner.Methods.ApplyForWithdraw, func() cborParams { return &fminer.WithdrawPledgeParams{} }, "AmountRequested")
+	registerMethod(ActorMiner, "WithdrawPledge", miner.Methods.WithdrawPledge, func() cborParams { return &fminer.WithdrawPledgeParams{} }, "AmountRequested")
+	registerMethod(ActorMiner, "TransferPledgeV2", miner.Methods.TransferPledgeV2, func() cborParams { return &fminer.TransferPledgeParamsV2{} }, "Amount")
+	registerMethod(ActorMiner, "ChangeWorkerAddress", builtin.MethodsMiner.ChangeWorkerAddress, func() cborParams { return &fminer.ChangeWorkerAddressParams{} })
+	registerMethod(ActorMiner, "ChangeOwnerAddress", builtin.MethodsMiner.ChangeOwnerAddress, addressParams)
+
+	registerMethod(ActorRetrieval, "Pledge", retrieval.Methods.Pledge, func() cborParams { return &retrieval.PledgeParams{} })
+	registerMethod(ActorRetrieval, "BindMiners", retrieval.Methods.BindMiners, func() cborParams { return &retrieval.BindMinersParams{} })
+	registerMethod(ActorRetrieval, "UnbindMiners", retrieval.Methods.UnbindMiners, func() cborParams { return &retrieval.BindMinersParams{} })
+	registerMethod(ActorRetrieval, "ApplyForWithdraw", retrieval.Methods.ApplyForWithdraw, func() cborParams { return &retrieval.WithdrawBalanceParams{} }, "Amount")
+	registerMethod(ActorRetrieval, "WithdrawBalance", retrieval.Methods.WithdrawBalance, amountParams, "")
+
+	registerMethod(ActorVesting, "WithdrawBalance", builtin.MethodsVesting.WithdrawBalance, func() cborParams { return &vesting.WithdrawBalanceParams{} }, "AmountRequested")
+
+	registerMethod(ActorMultisig, "Propose", builtin.MethodsMultisig.Propose, func() cborParams { return &multisig.ProposeParams{} }, "Value")
+	registerMethod(ActorMultisig, "Approve", builtin.MethodsMultisig.Approve, func() cborParams { return &multisig.TxnIDParams{} })
+	registerMethod(ActorMultisig, "Cancel", builtin.MethodsMultisig.Cancel, func() cborParams { return &multisig.TxnIDParams{} })
+}
+
+//ActorMethods JSON list of the registered methods of an actor kind, all kinds when actor is empty
+func ActorMethods(actor string) (methodsJSON string) {
+	out := []*ActorMethod{}
+	for kind, methods := range actorMethods {
+		if actor != "" && kind != actor {
+			continue
+		}
+		for _, m := range methods {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Actor != out[j].Actor {
+			return out[i].Actor < out[j].Actor
+		}
+		return out[i].Num < out[j].Num
+	})
+	data, _ := json.Marshal(out)
+	return string(data)
+}
+
+func lookupMethod(actor string, name string) (*ActorMethod, error) {
+	if strings.EqualFold(name, MethodSend) {
+		return &ActorMethod{Actor: actor, Name: MethodSend, Num: builtin.MethodSend}, nil
+	}
+	m, ok := actorMethods[actor][strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("method %s not found for actor %s", name, actor)
+	}
+	return m, nil
+}
+
+// actorParams serializes paramsJSON for a registered method, amounts are in EPK
+func actorParams(actor string, name string, paramsJSON string) (num abi.MethodNum, params []byte, err error) {
+	m, err := lookupMethod(actor, name)
+	if err != nil {
+		return
+	}
+	if m.params == nil {
+		if p := strings.TrimSpace(paramsJSON); p != "" && p != "null" && p != "{}" {
+			return 0, nil, fmt.Errorf("method %s takes no params", m.Name)
+		}
+		return m.Num, nil, nil
+	}
+	p := m.params()
+	data, err := parseParams(m, paramsJSON)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid params for %s: %w", m.Name, err)
+	}
+	if err = json.Unmarshal(data, p); err != nil {
+		return 0, nil, fmt.Errorf("invalid params for %s: %w", m.Name, err)
+	}
+	params, err = actors.SerializeParams(p)
+	if err != nil {
+		return
+	}
+	return m.Num, params, nil
+}
+
+// singletonKind knows the singleton actors by address
+func singletonKind(to address.Address) (actor string, ok bool) {
+	switch to {
+	case builtin.VoteFundActorAddr:
+		return ActorVote, true
+	case builtin.ExpertFundActorAddr:
+		return ActorExpertFund, true
+	case builtin.VestingActorAddr:
+		return ActorVesting, true
+	case retrieval.Address:
+		return ActorRetrieval, true
+	}
+	return "", false
+}
+
+// actorKind of to, other actors than the singletons are known by their code
+func actorKind(fullAPI api.FullNode, to address.Address) (actor string, err error) {
+	if actor, ok := singletonKind(to); ok {
+		return actor, nil
+	}
+	act, err := fullAPI.StateGetActor(context.Background(), to, types.EmptyTSK)
+	if err != nil {
+		return
+	}
+	return path.Base(builtin.ActorNameByCode(act.Code)), nil
+}
+
+//CreateActorMessage unsigned message JSON calling methodName of actor to with paramsJSON,
+//the actor kind is resolved from the chain, see ActorMethods for names and params.
+//Amounts in paramsJSON are in EPK like value, as DecodeMessage shows them
+func (w *Wallet) CreateActorMessage(to string, methodName string, paramsJSON string, value string) (message string, err error) {
+	fromAddr, err := w.epikWallet.GetDefault(context.Background())
+	if err != nil {
+		return "", err
+	}
+	toAddr, err := parseAddress(to)
+	if err != nil {
+		return
+	}
+	amount := big.Zero()
+	if value != "" {
+		epk, err := types.ParseEPK(value)
+		if err != nil {
+			return "", err
+		}
+		amount = big.Int(epk)
+	}
+	node, closer, err := w.fullAPI()
+	if err != nil {
+		return
+	}
+	defer closer()
+
+	actor := ""
+	if !strings.EqualFold(methodName, MethodSend) {
+		if actor, err = actorKind(node, toAddr); err != nil {
+			return
+		}
+	}
+	num, params, err := actorParams(actor, methodName, paramsJSON)
+	if err != nil {
+		return
+	}
+	msg := &types.Message{
+		From:   fromAddr,
+		To:     toAddr,
+		Value:  amount,
+		Method: num,
+		Params: params,
+	}
+	if err = w.fillMessage(node, msg); err != nil {
+		return
+	}
+	data, _ := json.Marshal(msg)
+	return string(data), nil
+}
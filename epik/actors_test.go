@@ -0,0 +1,166 @@
+package epik
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/EpiK-Protocol/go-epik/chain/actors"
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/vote"
+)
+
+func TestActorParams(t *testing.T) {
+	candidate, err := address.NewFromString("f01000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	votes, _ := types.ParseEPK("1.5")
+	want, err := actors.SerializeParams(&vote.RescindParams{
+		Candidate: candidate,
+		Votes:     types.BigInt(votes),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// amounts are in EPK, as DecodeMessage shows them
+	num, params, err := actorParams(ActorVote, "rescind", `{"Candidate":"f01000","Votes":"1.5"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num != builtin.MethodsVote.Rescind || !bytes.Equal(params, want) {
+		t.Fatalf("rescind: method %d params %x, want %x", num, params, want)
+	}
+
+	if _, params, err := actorParams(ActorVote, "rescind", `{"candidate":"f01000","votes":1.5}`); err != nil || !bytes.Equal(params, want) {
+		t.Fatalf("rescind with a number: params %x %v", params, err)
+	}
+	if _, _, err := actorParams(ActorVote, "rescind", `{"Candidate":"f01000","Votes":"many"}`); err == nil {
+		t.Fatal("invalid amount accepted")
+	}
+
+	num, params, err = actorParams(ActorMiner, "AddPledge", "")
+	if err != nil || num == 0 || params != nil {
+		t.Fatalf("add pledge: %d %x %v", num, params, err)
+	}
+	if _, _, err := actorParams(ActorMiner, "AddPledge", `{"Amount":"1"}`); err == nil {
+		t.Fatal("params accepted for a method without params")
+	}
+	if num, _, err := actorParams("", MethodSend, ""); err != nil || num != builtin.MethodSend {
+		t.Fatalf("send: %d %v", num, err)
+	}
+	if _, _, err := actorParams(ActorVote, "Unknown", ""); err == nil {
+		t.Fatal("unknown method accepted")
+	}
+}
+
+func TestFormatParams(t *testing.T) {
+	candidate, err := address.NewFromString("f01000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	votes, _ := types.ParseEPK("1.5")
+	m, err := lookupMethod(ActorVote, "Rescind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := formatParams(m, &vote.RescindParams{
+		Candidate: candidate,
+		Votes:     types.BigInt(votes),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Candidate":"` + candidate.String() + `","Votes":"1.5"}`
+	if string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+
+	// only registered amount fields are in EPK, other big integers stay as they are
+	power := &ActorMethod{Name: "Power"}
+	amount := abi.TokenAmount(votes)
+	if data, err := formatParams(power, &amount); err != nil || string(data) != `"1500000000000000000"` {
+		t.Fatalf("got %s %v", data, err)
+	}
+	m, err = lookupMethod(ActorRetrieval, "WithdrawBalance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := formatParams(m, &amount); err != nil || string(data) != `"1.5"` {
+		t.Fatalf("got %s %v", data, err)
+	}
+	if data, err := parseParams(m, "1.5"); err != nil || string(data) != `"1500000000000000000"` {
+		t.Fatalf("got %s %v", data, err)
+	}
+}
+
+func TestDecodeMessage(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := address.NewIDAddress(1001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, params, err := actorParams(ActorVote, "Rescind", `{"Candidate":"f01000","Votes":"1.5"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the vote actor is a singleton, no node is asked for its kind
+	decode := func(method abi.MethodNum, params []byte) *DecodedMessage {
+		message, err := json.Marshal(&types.Message{
+			From:       from,
+			To:         builtin.VoteFundActorAddr,
+			Nonce:      4,
+			Value:      types.NewInt(2000000000000000000),
+			GasLimit:   600000,
+			GasFeeCap:  types.NewInt(1000000000),
+			GasPremium: types.NewInt(100),
+			Method:     method,
+			Params:     params,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		decodedJSON, err := w.DecodeMessage(string(message))
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := &DecodedMessage{}
+		if err := json.Unmarshal([]byte(decodedJSON), decoded); err != nil {
+			t.Fatal(err)
+		}
+		return decoded
+	}
+
+	decoded := decode(builtin.MethodsVote.Rescind, params)
+	if decoded.Actor != ActorVote || decoded.Method != "Rescind" || decoded.Error != "" {
+		t.Fatalf("unexpected call: %+v", decoded)
+	}
+	if decoded.Value != "2" || decoded.MaxFee != "0.0006" || decoded.Nonce != 4 {
+		t.Fatalf("unexpected amounts: %+v", decoded)
+	}
+	candidate, _ := address.NewIDAddress(1000)
+	if want := `{"Candidate":"` + candidate.String() + `","Votes":"1.5"}`; string(decoded.Params) != want {
+		t.Fatalf("params %s, want %s", decoded.Params, want)
+	}
+
+	// undecodable params are returned raw next to the error
+	bad := []byte{0x82, 0x01}
+	decoded = decode(builtin.MethodsVote.Rescind, bad)
+	if decoded.Error == "" || !bytes.Equal(decoded.RawParams, bad) || string(decoded.Params) != "null" {
+		t.Fatalf("bad params: %+v", decoded)
+	}
+	decoded = decode(99, bad)
+	if decoded.Error == "" || decoded.Method != "Method 99" || !bytes.Equal(decoded.RawParams, bad) {
+		t.Fatalf("unknown method: %+v", decoded)
+	}
+	decoded = decode(builtin.MethodSend, nil)
+	if decoded.Method != MethodSend || decoded.Error != "" || decoded.Value != "2" {
+		t.Fatalf("send: %+v", decoded)
+	}
+}
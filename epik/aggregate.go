@@ -0,0 +1,78 @@
+package epik
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/epik/bls"
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/crypto"
+)
+
+//AggregateSignatures signaturesJSON: ["hex of Sign output", ...], bls signatures only
+func (w *Wallet) AggregateSignatures(signaturesJSON string) (signature []byte, err error) {
+	list := []string{}
+	err = json.Unmarshal([]byte(signaturesJSON), &list)
+	if err != nil {
+		return
+	}
+	raw := make([][]byte, 0, len(list))
+	for _, s := range list {
+		data, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		sign := &crypto.Signature{}
+		err = sign.UnmarshalBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		if sign.Type != crypto.SigTypeBLS {
+			return nil, fmt.Errorf("only bls signatures can be aggregated")
+		}
+		raw = append(raw, sign.Data)
+	}
+	agg, err := bls.Aggregate(raw)
+	if err != nil {
+		return
+	}
+	sign := &crypto.Signature{
+		Type: crypto.SigTypeBLS,
+		Data: agg,
+	}
+	return sign.MarshalBinary()
+}
+
+//VerifyAggregate itemsJSON: [{"address":"f3...","message":"hex"}, ...], messages must be distinct
+func (w *Wallet) VerifyAggregate(signature []byte, itemsJSON string) (valid bool, err error) {
+	items := []struct {
+		Address string `json:"address"`
+		Message string `json:"message"`
+	}{}
+	err = json.Unmarshal([]byte(itemsJSON), &items)
+	if err != nil {
+		return
+	}
+	sign := &crypto.Signature{}
+	err = sign.UnmarshalBinary(signature)
+	if err != nil {
+		return
+	}
+	if sign.Type != crypto.SigTypeBLS {
+		return false, fmt.Errorf("not a bls signature")
+	}
+	addrs := make([]address.Address, len(items))
+	msgs := make([][]byte, len(items))
+	for i, item := range items {
+		addrs[i], err = parseAddress(item.Address)
+		if err != nil {
+			return
+		}
+		msgs[i], err = hex.DecodeString(item.Message)
+		if err != nil {
+			return
+		}
+	}
+	return bls.VerifyAggregate(sign.Data, addrs, msgs) == nil, nil
+}
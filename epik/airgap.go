@@ -0,0 +1,87 @@
+package epik
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/airgap"
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+)
+
+// Air-gapped signing moves the PrepareMessage, SignMessage and
+// BroadcastSignedMessage payloads as CBOR airgap envelopes, see
+// airgap.Split for multi-part QR codes.
+
+//MessageEnvelope encodes a prepared message JSON as an airgap envelope
+func MessageEnvelope(message string) (envelope string, err error) {
+	msg := &types.Message{}
+	if err = json.Unmarshal([]byte(message), msg); err != nil {
+		return
+	}
+	data, err := msg.Serialize()
+	if err != nil {
+		return
+	}
+	return airgap.Encode(airgap.KindEpiKMessage, data)
+}
+
+//SignMessageEnvelope signs a message envelope offline, returns a signed message envelope
+func (w *Wallet) SignMessageEnvelope(envelope string) (signedEnvelope string, err error) {
+	payload, err := airgap.DecodeKind(envelope, airgap.KindEpiKMessage)
+	if err != nil {
+		return
+	}
+	msg, err := types.DecodeMessage(payload)
+	if err != nil {
+		return
+	}
+	message, _ := json.Marshal(msg)
+	signedJSON, err := w.SignMessage(string(message))
+	if err != nil {
+		return
+	}
+	signed := &types.SignedMessage{}
+	if err = json.Unmarshal([]byte(signedJSON), signed); err != nil {
+		return
+	}
+	data, err := signed.Serialize()
+	if err != nil {
+		return
+	}
+	return airgap.Encode(airgap.KindEpiKSignedMessage, data)
+}
+
+//BroadcastSignedEnvelope pushes a signed message envelope
+func (w *Wallet) BroadcastSignedEnvelope(signedEnvelope string) (cidStr string, err error) {
+	payload, err := airgap.DecodeKind(signedEnvelope, airgap.KindEpiKSignedMessage)
+	if err != nil {
+		return
+	}
+	signed, err := types.DecodeSignedMessage(payload)
+	if err != nil {
+		return
+	}
+	return w.pushSignedMessage(signed)
+}
+
+//EnvelopeJSON Message or SignedMessage JSON of an envelope, for review before signing or pushing
+func EnvelopeJSON(envelope string) (messageJSON string, err error) {
+	e, err := airgap.Decode(envelope)
+	if err != nil {
+		return
+	}
+	var v interface{}
+	switch e.Kind {
+	case airgap.KindEpiKMessage:
+		v, err = types.DecodeMessage(e.Payload)
+	case airgap.KindEpiKSignedMessage:
+		v, err = types.DecodeSignedMessage(e.Payload)
+	default:
+		return "", fmt.Errorf("not an epik message envelope: kind %d", e.Kind)
+	}
+	if err != nil {
+		return
+	}
+	data, _ := json.Marshal(v)
+	return string(data), nil
+}
@@ -0,0 +1,122 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// EIP-2333 hierarchical BLS key derivation, paths follow EIP-2334: m/12381/coin/account/use
+
+// order of the BLS12-381 group
+var blsR, _ = new(big.Int).SetString("52435875175126190479447740508185965837690552500527637822603658699938581184513", 10)
+
+const lamportChunks = 255
+
+//DeriveMasterSK EIP-2333 master secret key, seed at least 32 bytes
+func DeriveMasterSK(seed []byte) (*big.Int, error) {
+	if len(seed) < 32 {
+		return nil, fmt.Errorf("eip2333 seed must be at least 32 bytes")
+	}
+	return hkdfModR(seed, nil)
+}
+
+//DeriveChildSK EIP-2333 child secret key
+func DeriveChildSK(parentSK *big.Int, index uint32) (*big.Int, error) {
+	return hkdfModR(parentSKToLamportPK(parentSK, index), nil)
+}
+
+//DerivePath derives the key at an EIP-2334 path (m/12381/...), returns the little-endian private key
+func DerivePath(seed []byte, path string) ([]byte, error) {
+	indexes, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := DeriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		sk, err = DeriveChildSK(sk, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// private keys are serialized little-endian
+	be := sk.FillBytes(make([]byte, 32))
+	le := make([]byte, 32)
+	for i := range be {
+		le[i] = be[31-i]
+	}
+	return le, nil
+}
+
+func parsePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, fmt.Errorf("eip2333 path must start with m: %s", path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, p := range parts[1:] {
+		n, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("eip2333 invalid path index %q: %s", p, path)
+		}
+		indexes = append(indexes, uint32(n))
+	}
+	return indexes, nil
+}
+
+func hkdfModR(ikm []byte, keyInfo []byte) (*big.Int, error) {
+	const l = 48 // ceil((3 * ceil(log2(r))) / 16)
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		okm := make([]byte, l)
+		info := append(append([]byte{}, keyInfo...), 0, l)
+		r := hkdf.New(sha256.New, append(append([]byte{}, ikm...), 0), salt, info)
+		if _, err := io.ReadFull(r, okm); err != nil {
+			return nil, err
+		}
+		sk.SetBytes(okm).Mod(sk, blsR)
+	}
+	return sk, nil
+}
+
+func ikmToLamportSK(ikm []byte, salt []byte) ([][]byte, error) {
+	okm := make([]byte, 32*lamportChunks)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, nil), okm); err != nil {
+		return nil, err
+	}
+	chunks := make([][]byte, lamportChunks)
+	for i := range chunks {
+		chunks[i] = okm[i*32 : (i+1)*32]
+	}
+	return chunks, nil
+}
+
+func parentSKToLamportPK(parentSK *big.Int, index uint32) []byte {
+	salt := []byte{byte(index >> 24), byte(index >> 16), byte(index >> 8), byte(index)}
+	ikm := parentSK.FillBytes(make([]byte, 32))
+	notIkm := make([]byte, len(ikm))
+	for i, b := range ikm {
+		notIkm[i] = ^b
+	}
+	// 32*255 bytes is far below the HKDF-SHA256 output limit, reads can't fail
+	lamport0, _ := ikmToLamportSK(ikm, salt)
+	lamport1, _ := ikmToLamportSK(notIkm, salt)
+
+	h := sha256.New()
+	for _, sk := range append(lamport0, lamport1...) {
+		sum := sha256.Sum256(sk)
+		h.Write(sum[:])
+	}
+	return h.Sum(nil)
+}
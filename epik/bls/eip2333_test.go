@@ -0,0 +1,36 @@
+package bls
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// test case 0 of EIP-2333
+func TestEIP2333(t *testing.T) {
+	seed, _ := hex.DecodeString("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04")
+	master, err := DeriveMasterSK(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if master.String() != "6083874454709270928345386274498605044986640685124978867557563392430687146096" {
+		t.Fatalf("unexpected master SK: %s", master)
+	}
+	child, err := DeriveChildSK(master, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child.String() != "20397789859736650942317412262472558107875392172444076792671091975210932703118" {
+		t.Fatalf("unexpected child SK: %s", child)
+	}
+
+	pk, err := DerivePath(seed, "m/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (blsSigner{}).ToPublic(pk); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DerivePath(seed, "m/12381'/0"); err == nil {
+		t.Fatal("hardened index accepted")
+	}
+}
@@ -1,6 +1,7 @@
 package bls
 
 import (
+	"crypto/rand"
 	"fmt"
 
 	"github.com/filecoin-project/go-address"
@@ -21,14 +22,14 @@ type blsSigner struct{}
 
 func (blsSigner) GenPrivate() ([]byte, error) {
 	// Generate 32 bytes of randomness
-	// var ikm [32]byte
-	// _, err := rand.Read(ikm[:])
-	// if err != nil {
-	// 	return nil, fmt.Errorf("bls signature error generating random data")
-	// }
-	// // Note private keys seem to be serialized little-endian!
-	// pk := blst.KeyGen(ikm[:]).ToLEndian()
-	return nil, nil
+	var ikm [32]byte
+	_, err := rand.Read(ikm[:])
+	if err != nil {
+		return nil, fmt.Errorf("bls signature error generating random data")
+	}
+	// Note private keys seem to be serialized little-endian!
+	pk := blst.KeyGen(ikm[:]).ToLEndian()
+	return pk, nil
 }
 
 func (blsSigner) GenPrivateFromSeed(seed []byte) ([]byte, error) {
@@ -65,6 +66,51 @@ func (blsSigner) Verify(sig []byte, a address.Address, msg []byte) error {
 	return nil
 }
 
+// Aggregate combines compressed signatures into a single compressed signature.
+func Aggregate(sigs [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, fmt.Errorf("bls aggregate no signatures")
+	}
+	points := make([]*Signature, len(sigs))
+	for i, sig := range sigs {
+		points[i] = new(Signature).Uncompress(sig)
+		if points[i] == nil {
+			return nil, fmt.Errorf("bls aggregate invalid signature %d", i)
+		}
+	}
+	agg := new(AggregateSignature)
+	if !agg.Aggregate(points) {
+		return nil, fmt.Errorf("bls aggregate failed")
+	}
+	return agg.ToAffine().Compress(), nil
+}
+
+// VerifyAggregate checks an aggregate signature over msgs[i] signed by addrs[i].
+// Messages must be distinct, otherwise a rogue key could forge the aggregate.
+func VerifyAggregate(sig []byte, addrs []address.Address, msgs [][]byte) error {
+	if len(addrs) == 0 || len(addrs) != len(msgs) {
+		return fmt.Errorf("bls aggregate %d addresses for %d messages", len(addrs), len(msgs))
+	}
+	seen := make(map[string]struct{}, len(msgs))
+	pks := make([][]byte, len(addrs))
+	blsMsgs := make([]blst.Message, len(msgs))
+	for i, a := range addrs {
+		if a.Protocol() != address.BLS {
+			return fmt.Errorf("bls aggregate not a bls address: %s", a)
+		}
+		if _, ok := seen[string(msgs[i])]; ok {
+			return fmt.Errorf("bls aggregate duplicate message %d", i)
+		}
+		seen[string(msgs[i])] = struct{}{}
+		pks[i] = a.Payload()
+		blsMsgs[i] = msgs[i]
+	}
+	if !new(Signature).AggregateVerifyCompressed(sig, pks, blsMsgs, []byte(DST)) {
+		return fmt.Errorf("bls aggregate signature failed to verify")
+	}
+	return nil
+}
+
 func init() {
 	sigs.RegisterSignature(crypto.SigTypeBLS, blsSigner{})
 }
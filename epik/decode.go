@@ -0,0 +1,179 @@
+package epik
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/shopspring/decimal"
+)
+
+//DecodedMessage what a message does, amounts are in EPK
+type DecodedMessage struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Actor     string          `json:"actor"`
+	Method    string          `json:"method"`
+	MethodNum abi.MethodNum   `json:"method_num"`
+	Value     string          `json:"value"`
+	MaxFee    string          `json:"max_fee"`
+	Nonce     uint64          `json:"nonce"`
+	Params    json.RawMessage `json:"params"`
+	RawParams []byte          `json:"raw_params,omitempty"`
+	// Error tells why the actor or params could not be decoded, the rest is still valid
+	Error string `json:"error,omitempty"`
+}
+
+//DecodeMessage explains a message JSON before it is signed, the node is asked
+//for the actor type unless the target is a singleton actor
+func (w *Wallet) DecodeMessage(message string) (decodedJSON string, err error) {
+	msg := &types.Message{}
+	if err = json.Unmarshal([]byte(message), msg); err != nil {
+		return
+	}
+	decoded := &DecodedMessage{
+		From:      msg.From.String(),
+		To:        msg.To.String(),
+		Method:    fmt.Sprintf("Method %d", msg.Method),
+		MethodNum: msg.Method,
+		Value:     formatEPK(msg.Value),
+		MaxFee:    "0",
+		Nonce:     msg.Nonce,
+		Params:    json.RawMessage("null"),
+	}
+	if !msg.GasFeeCap.Nil() {
+		decoded.MaxFee = formatEPK(big.Mul(msg.GasFeeCap, big.NewInt(msg.GasLimit)))
+	}
+	if err := w.decodeCall(msg, decoded); err != nil {
+		decoded.Error = err.Error()
+		if len(msg.Params) > 0 {
+			decoded.RawParams = msg.Params
+		}
+	}
+	data, _ := json.Marshal(decoded)
+	return string(data), nil
+}
+
+func (w *Wallet) decodeCall(msg *types.Message, decoded *DecodedMessage) error {
+	if msg.Method == 0 {
+		decoded.Method = MethodSend
+		return nil
+	}
+	actor, ok := singletonKind(msg.To)
+	if !ok {
+		node, closer, err := w.fullAPI()
+		if err != nil {
+			return err
+		}
+		defer closer()
+		if actor, err = actorKind(node, msg.To); err != nil {
+			return err
+		}
+	}
+	decoded.Actor = actor
+
+	var m *ActorMethod
+	for _, am := range actorMethods[actor] {
+		if am.Num == msg.Method {
+			m = am
+			break
+		}
+	}
+	if m == nil {
+		return fmt.Errorf("method %d not found for actor %s", msg.Method, actor)
+	}
+	decoded.Method = m.Name
+	if m.params == nil {
+		return nil
+	}
+	p := m.params()
+	if err := p.UnmarshalCBOR(bytes.NewReader(msg.Params)); err != nil {
+		return fmt.Errorf("decoding params of %s: %w", m.Name, err)
+	}
+	data, err := formatParams(m, p)
+	if err != nil {
+		return err
+	}
+	decoded.Params = data
+	return nil
+}
+
+func formatEPK(amount big.Int) string {
+	if amount.Nil() {
+		return "0"
+	}
+	return decimal.NewFromBigInt(amount.Int, -18).String()
+}
+
+// formatParams is the JSON of decoded params, with the amount fields of m written in EPK
+func formatParams(m *ActorMethod, p cborParams) (json.RawMessage, error) {
+	data, err := json.Marshal(p)
+	if err != nil || len(m.Amounts) == 0 {
+		return data, err
+	}
+	v := reflect.ValueOf(p).Elem()
+	if m.Amounts[0] == "" {
+		return json.Marshal(formatEPK(v.Interface().(big.Int)))
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range m.Amounts {
+		if fields[name], err = json.Marshal(formatEPK(v.FieldByName(name).Interface().(big.Int))); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// parseParams is the inverse of formatParams, the EPK amounts of paramsJSON
+// are written back in attoEPK.
+func parseParams(m *ActorMethod, paramsJSON string) (json.RawMessage, error) {
+	if len(m.Amounts) == 0 {
+		return json.RawMessage(paramsJSON), nil
+	}
+	if m.Amounts[0] == "" {
+		return parseAmount(json.RawMessage(paramsJSON))
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(paramsJSON), &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		for _, name := range m.Amounts {
+			// encoding/json matches field names case insensitively
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+			parsed, err := parseAmount(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			fields[key] = parsed
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// parseAmount takes an EPK amount as a JSON string or number
+func parseAmount(value json.RawMessage) (json.RawMessage, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(value, &n); err != nil {
+			return nil, fmt.Errorf("amount is not a string or number: %s", value)
+		}
+		s = n.String()
+	}
+	epk, err := types.ParseEPK(s)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(big.Int(epk).String())
+}
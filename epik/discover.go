@@ -0,0 +1,181 @@
+package epik
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/epik/wallet"
+	"github.com/EpiK-Protocol/epik-wallet-golib/recovery"
+	"github.com/EpiK-Protocol/go-epik/api"
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+	"github.com/shopspring/decimal"
+)
+
+//DerivationConvention path template, key type and scheme used by a wallet
+type DerivationConvention struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// conventions tried by DiscoverAccounts when none are given
+var defaultConventions = []DerivationConvention{
+	{Name: "epik-bls", Path: "m/44'/196'/1'/0/{index}", Type: "bls", Scheme: KeySchemeLegacy},
+	{Name: "epik-secp256k1", Path: "m/44'/196'/1'/0/{index}", Type: "secp256k1", Scheme: KeySchemeLegacy},
+	{Name: "filecoin-secp256k1", Path: "m/44'/461'/0'/0/{index}", Type: "secp256k1", Scheme: KeySchemeBIP32},
+	{Name: "filecoin-legacy-bls", Path: "m/44'/461'/0'/0/{index}", Type: "bls", Scheme: KeySchemeLegacy},
+	{Name: "eip2333-bls", Path: "m/12381/461/0/{index}", Type: "bls", Scheme: KeySchemeEIP2333},
+}
+
+//DiscoveredAccount ...
+type DiscoveredAccount struct {
+	Convention string `json:"convention"`
+	Path       string `json:"path"`
+	Type       string `json:"type"`
+	Scheme     string `json:"scheme"`
+	Address    string `json:"address"`
+	Exists     bool   `json:"exists"`
+	Balance    string `json:"balance"`
+	Nonce      uint64 `json:"nonce"`
+}
+
+//DefaultConventions JSON list of the conventions DiscoverAccounts tries by default
+func DefaultConventions() string {
+	data, _ := json.Marshal(defaultConventions)
+	return string(data)
+}
+
+//DiscoverAccounts derives indexes 0..count-1 of every convention (default conventions when conventionsJSON is empty)
+//and reports whether each address exists on chain. Nothing is imported, use GenerateKeyWithScheme for the accounts to keep.
+func (w *Wallet) DiscoverAccounts(seed []byte, conventionsJSON string, count int) (accountsJSON string, err error) {
+	conventions := defaultConventions
+	if strings.TrimSpace(conventionsJSON) != "" {
+		conventions = nil
+		if err := json.Unmarshal([]byte(conventionsJSON), &conventions); err != nil {
+			return "", err
+		}
+	}
+	if count <= 0 {
+		count = 1
+	}
+
+	ctx := context.Background()
+	node, closer, err := w.fullAPI()
+	if err != nil {
+		return "", err
+	}
+	defer closer()
+
+	accounts := []*DiscoveredAccount{}
+	for _, c := range conventions {
+		typ, err := parseKeyType(c.Type)
+		if err != nil {
+			return "", fmt.Errorf("convention %s: %w", c.Name, err)
+		}
+		n := count
+		if !strings.Contains(c.Path, recovery.PathIndex) {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			path := strings.ReplaceAll(c.Path, recovery.PathIndex, strconv.Itoa(i))
+			ki, err := deriveKeyInfo(typ, seed, path, c.Scheme)
+			if err != nil {
+				return "", fmt.Errorf("convention %s: %w", c.Name, err)
+			}
+			k, err := wallet.NewKey(ki)
+			if err != nil {
+				return "", fmt.Errorf("convention %s: %w", c.Name, err)
+			}
+			account := &DiscoveredAccount{
+				Convention: c.Name,
+				Path:       path,
+				Type:       c.Type,
+				Scheme:     c.Scheme,
+				Address:    k.Address.String(),
+				Balance:    "0",
+			}
+			actor, err := node.StateGetActor(ctx, k.Address, types.EmptyTSK)
+			if err != nil {
+				if !actorNotFound(err) {
+					return "", err
+				}
+			} else {
+				account.Exists = true
+				account.Balance = decimal.NewFromBigInt(actor.Balance.Int, -18).String()
+				account.Nonce = actor.Nonce
+			}
+			accounts = append(accounts, account)
+		}
+	}
+	data, _ := json.Marshal(accounts)
+	return string(data), nil
+}
+
+//RecoverAccounts walks the indexes of pathTemplate (e.g. m/44'/196'/1'/0/{index}) until gapLimit consecutive
+//addresses have no balance and no messages, imports the used ones and returns them with their paths.
+//t:bls,secp256k1 scheme:legacy,bip32,eip2333
+func (w *Wallet) RecoverAccounts(t string, seed []byte, pathTemplate string, scheme string, gapLimit int) (accountsJSON string, err error) {
+	typ, err := parseKeyType(t)
+	if err != nil {
+		return "", err
+	}
+	node, closer, err := w.fullAPI()
+	if err != nil {
+		return "", err
+	}
+	defer closer()
+	recovered, err := w.recoverAccounts(node, typ, seed, pathTemplate, scheme, gapLimit)
+	if err != nil {
+		return "", err
+	}
+	data, _ := json.Marshal(recovered)
+	return string(data), nil
+}
+
+func (w *Wallet) recoverAccounts(node api.FullNode, typ types.KeyType, seed []byte, pathTemplate string, scheme string, gapLimit int) ([]*recovery.RecoveredAccount, error) {
+	ctx := context.Background()
+	return recovery.Walk(pathTemplate, gapLimit, func(path string) (*recovery.RecoveredAccount, bool, error) {
+		ki, err := deriveKeyInfo(typ, seed, path, scheme)
+		if err != nil {
+			return nil, false, err
+		}
+		k, err := wallet.NewKey(ki)
+		if err != nil {
+			return nil, false, err
+		}
+		bal, err := node.WalletBalance(ctx, k.Address)
+		if err != nil {
+			return nil, false, err
+		}
+		nonce, err := node.MpoolGetNonce(ctx, k.Address)
+		if err != nil {
+			// addresses that never received funds have no actor
+			if !actorNotFound(err) {
+				return nil, false, err
+			}
+			nonce = 0
+		}
+		if bal.IsZero() && nonce == 0 {
+			return nil, false, nil
+		}
+		if _, err := w.epikWallet.WalletNewFromKeyInfo(ki); err != nil {
+			return nil, false, err
+		}
+		return &recovery.RecoveredAccount{
+			Path:    path,
+			Address: k.Address.String(),
+			Balance: decimal.NewFromBigInt(bal.Int, -18).String(),
+			Nonce:   nonce,
+		}, true, nil
+	})
+}
+
+// actorNotFound is the only check for a missing actor that works against a
+// remote node: errors lose their type over RPC, only the message is left.
+func actorNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), types.ErrActorNotFound.Error())
+}
@@ -0,0 +1,114 @@
+package epik
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/epik/wallet"
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+	"github.com/filecoin-project/go-address"
+	"github.com/tyler-smith/go-bip39"
+)
+
+func TestDiscoverAccounts(t *testing.T) {
+	seed := bip39.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	used, err := address.NewFromString("f12nzdrhfh6caurft7gwy6d3uazvgy3lhl7rfzvpq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dialStub(t, &stubNode{
+		actors: map[address.Address]*types.Actor{used: {Nonce: 5, Balance: types.NewInt(3000000000000000000)}},
+	})
+
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountsJSON, err := w.DiscoverAccounts(seed, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := []*DiscoveredAccount{}
+	if err := json.Unmarshal([]byte(accountsJSON), &accounts); err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 2*len(defaultConventions) {
+		t.Fatalf("%d accounts", len(accounts))
+	}
+	// the filecoin conventions share the Glif path
+	want := map[string]string{
+		"filecoin-secp256k1/m/44'/461'/0'/0/0":  "1qode47ievxlxzk6z2viuovedabmn3tq6t57uqhq",
+		"filecoin-secp256k1/m/44'/461'/0'/0/1":  "12nzdrhfh6caurft7gwy6d3uazvgy3lhl7rfzvpq",
+		"filecoin-legacy-bls/m/44'/461'/0'/0/0": "3vwd735eabdzsrb7524y7h36bsuj3dezlr3gd6ydwexykyyddqzw7jij2dpdrsdr22zoj3u2nc5gwuv4d4jqa",
+		"filecoin-legacy-bls/m/44'/461'/0'/0/1": "3ssanhtshxvapvuwh6mib4rm2v7getdieddh33ibkf4ebjeywa2rtccrtkavs67rggprfc2m3aya3aqvwkw4a",
+	}
+	for _, a := range accounts {
+		if addr, ok := want[a.Convention+"/"+a.Path]; ok {
+			if a.Address[1:] != addr {
+				t.Fatalf("%s %s: address %s, want ?%s", a.Convention, a.Path, a.Address, addr)
+			}
+			delete(want, a.Convention+"/"+a.Path)
+		}
+		exists := a.Address[1:] == used.String()[1:]
+		if a.Exists != exists {
+			t.Fatalf("%s: exists %v", a.Address, a.Exists)
+		}
+		if exists && (a.Balance != "3" || a.Nonce != 5) {
+			t.Fatalf("unexpected account: %+v", a)
+		}
+		if !exists && a.Balance != "0" {
+			t.Fatalf("unexpected account: %+v", a)
+		}
+	}
+	if len(want) != 0 {
+		t.Fatalf("missing accounts: %v", want)
+	}
+}
+
+// the gap limit walk is tested in recovery, this covers the node lookups and the import
+func TestRecoverAccounts(t *testing.T) {
+	seed := bip39.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	addrs := make([]address.Address, 2)
+	for i := range addrs {
+		ki, err := deriveKeyInfo(types.KTBLS, seed, fmt.Sprintf("m/44'/196'/1'/0/%d", i), KeySchemeLegacy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, err := wallet.NewKey(ki)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs[i] = k.Address
+	}
+	// index 0 holds funds, index 1 only sent messages, index 2 is unused
+	node := &stubNode{
+		balances: map[address.Address]types.BigInt{addrs[0]: types.NewInt(1500000000000000000)},
+		nonces:   map[address.Address]uint64{addrs[1]: 2},
+	}
+
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := w.recoverAccounts(node, types.KTBLS, seed, "m/44'/196'/1'/0/{index}", KeySchemeLegacy, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(recovered)
+	want := fmt.Sprintf(`[{"path":"m/44'/196'/1'/0/0","address":"%s","balance":"1.5","nonce":0},{"path":"m/44'/196'/1'/0/1","address":"%s","balance":"0","nonce":2}]`, addrs[0], addrs[1])
+	if string(data) != want {
+		t.Fatalf("recovered %s, want %s", data, want)
+	}
+	list, err := w.AddrList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := map[string]bool{}
+	for _, a := range list {
+		imported[a] = true
+	}
+	if len(list) != 2 || !imported[addrs[0].String()] || !imported[addrs[1].String()] {
+		t.Fatalf("imported %v", list)
+	}
+}
@@ -3,16 +3,18 @@ package epik
 import (
 	"bytes"
 	"context"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"reflect"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/EpiK-Protocol/epik-wallet-golib/epik/bls"
 	"github.com/EpiK-Protocol/epik-wallet-golib/epik/client"
 	"github.com/EpiK-Protocol/epik-wallet-golib/epik/wallet"
+	"github.com/EpiK-Protocol/epik-wallet-golib/meta"
 	"github.com/EpiK-Protocol/go-epik/api"
 	"github.com/EpiK-Protocol/go-epik/chain/actors"
 	"github.com/EpiK-Protocol/go-epik/chain/actors/builtin/expert"
@@ -20,6 +22,7 @@ import (
 	"github.com/EpiK-Protocol/go-epik/chain/actors/builtin/retrieval"
 	vesting2 "github.com/EpiK-Protocol/go-epik/chain/actors/builtin/vesting"
 	"github.com/EpiK-Protocol/go-epik/chain/types"
+	"github.com/EpiK-Protocol/go-epik/lib/sigs"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/ethereum/go-ethereum/accounts"
@@ -44,6 +47,15 @@ type Wallet struct {
 	epikWallet *wallet.LocalWallet
 	rpcURL     string
 	header     http.Header
+
+	autoLock   time.Duration
+	lastActive time.Time
+	lockTimer  *time.Timer
+	lockLk     sync.Mutex
+
+	metaStore *meta.Store
+
+	nonces nonceTracker
 }
 
 //PrivateKey ...
@@ -78,62 +90,170 @@ func NewWallet() (w *Wallet, err error) {
 	return w, nil
 }
 
+//NewWalletWithKeystore wallet whose keys are encrypted with passphrase and kept in dir
+func NewWalletWithKeystore(dir string, passphrase string) (w *Wallet, err error) {
+	ks, err := wallet.NewFsKeyStore(dir, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	wa, err := wallet.NewWallet(ks)
+	if err != nil {
+		return nil, err
+	}
+	w = &Wallet{
+		epikWallet: wa,
+	}
+	return w, nil
+}
+
 //GenerateKey t:bls,secp256k1
 func (w *Wallet) GenerateKey(t string, seed []byte, path string) (addrStr string, err error) {
 	seed, err = epikHDPathSeed(seed, path)
 	if err != nil {
 		return "", err
 	}
-	var addr address.Address
+	typ, err := parseKeyType(t)
+	if err != nil {
+		return "", err
+	}
+	addr, err := w.epikWallet.WalletNewFromSeed(typ, seed)
+	if err != nil {
+		return "", err
+	}
+	return addr.String(), nil
+}
+
+// key derivation schemes for GenerateKeyWithScheme
+const (
+	// KeySchemeLegacy BIP32 child key fed into the bls/secp256k1 seed key generation, used by GenerateKey
+	KeySchemeLegacy = "legacy"
+	// KeySchemeBIP32 the BIP32 child key is the secp256k1 key, as in Glif and other Filecoin wallets
+	KeySchemeBIP32 = "bip32"
+	// KeySchemeEIP2333 standard BLS derivation (EIP-2333), paths like m/12381/461/0/0
+	KeySchemeEIP2333 = "eip2333"
+)
+
+//GenerateKeyWithScheme t:bls,secp256k1 scheme:legacy,bip32 (secp256k1 only),eip2333 (bls only)
+func (w *Wallet) GenerateKeyWithScheme(t string, seed []byte, path string, scheme string) (addrStr string, err error) {
+	typ, err := parseKeyType(t)
+	if err != nil {
+		return "", err
+	}
+	ki, err := deriveKeyInfo(typ, seed, path, scheme)
+	if err != nil {
+		return "", err
+	}
+	addr, err := w.epikWallet.WalletNewFromKeyInfo(ki)
+	if err != nil {
+		return "", err
+	}
+	return addr.String(), nil
+}
+
+func deriveKeyInfo(typ types.KeyType, seed []byte, path string, scheme string) (ki types.KeyInfo, err error) {
+	ki.Type = typ
+	switch strings.ToLower(scheme) {
+	case KeySchemeLegacy, "":
+		pathSeed, err := epikHDPathSeed(seed, path)
+		if err != nil {
+			return ki, err
+		}
+		k, err := wallet.GenerateKeyFromSeed(typ, pathSeed)
+		if err != nil {
+			return ki, err
+		}
+		return k.KeyInfo, nil
+	case KeySchemeBIP32:
+		if typ != types.KTSecp256k1 {
+			return ki, fmt.Errorf("scheme %s only supports secp256k1 keys", scheme)
+		}
+		ki.PrivateKey, err = epikHDPathSeed(seed, path)
+		return ki, err
+	case KeySchemeEIP2333:
+		if typ != types.KTBLS {
+			return ki, fmt.Errorf("scheme %s only supports bls keys", scheme)
+		}
+		ki.PrivateKey, err = bls.DerivePath(seed, path)
+		return ki, err
+	default:
+		return ki, fmt.Errorf("key scheme not support: %s", scheme)
+	}
+}
+
+//NewKey random (non-HD) key, t:bls,secp256k1
+func (w *Wallet) NewKey(t string) (addrStr string, err error) {
+	typ, err := parseKeyType(t)
+	if err != nil {
+		return "", err
+	}
+	addr, err := w.epikWallet.WalletNew(context.Background(), typ)
+	if err != nil {
+		return "", err
+	}
+	return addr.String(), nil
+}
+
+func parseKeyType(t string) (typ types.KeyType, err error) {
 	switch strings.ToLower(t) {
 	case "bls":
-		addr, err = w.epikWallet.WalletNewFromSeed(types.KTBLS, seed)
+		return types.KTBLS, nil
 	case "secp256k1":
-		addr, err = w.epikWallet.WalletNewFromSeed(types.KTSecp256k1, seed)
+		return types.KTSecp256k1, nil
 	default:
 		return "", fmt.Errorf("SigType not suppot")
 	}
+}
+
+// epikHDPathSeed derives the BIP32 private key at path and returns it as a
+// 32 byte big-endian seed for bls/secp256k1 key generation. Every existing
+// address depends on these bytes, see TestHDPathVectors before changing it.
+func epikHDPathSeed(seed []byte, path string) (pathSeed []byte, err error) {
+	key, err := epikHDKey(seed, path)
+	if err != nil {
+		return nil, err
+	}
+	// ECPrivKey fails for neutered (public) keys instead of returning the public key bytes
+	priv, err := key.ECPrivKey()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return addr.String(), nil
+	// Serialize left-pads to 32 bytes
+	return priv.Serialize(), nil
 }
 
-func epikHDPathSeed(seed []byte, path string) (pathSeed []byte, err error) {
+func epikHDKey(seed []byte, path string) (key *hdkeychain.ExtendedKey, err error) {
 	masterKey, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
 	if err != nil {
 		return nil, err
 	}
+	return deriveHDKey(masterKey, path)
+}
+
+func deriveHDKey(key *hdkeychain.ExtendedKey, path string) (*hdkeychain.ExtendedKey, error) {
 	p, err := accounts.ParseDerivationPath(path)
 	if err != nil {
 		return nil, err
 	}
-	key := masterKey
 	for _, n := range p {
 		key, err = key.Derive(n)
 		if err != nil {
 			return nil, err
 		}
 	}
-	rawSeed := reflect.ValueOf(key).Elem().FieldByName("key").Bytes()
-	seed = make([]byte, 32)
-	if len(rawSeed) <= 32 {
-		copy(seed[32-len(rawSeed):], rawSeed[:])
-	} else {
-		copy(seed[:], rawSeed[:])
-	}
-
-	return seed, nil
-
+	return key, nil
 }
 
-//AddrList ...
+//AddrList keys held by the wallet, followed by watch-only addresses
 func (w *Wallet) AddrList() (addrs []string, err error) {
 	ads, err := w.epikWallet.WalletList(context.Background())
 	if err != nil {
 		return nil, err
 	}
-	for _, ad := range ads {
+	watch, err := w.epikWallet.WalletListWatch(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for _, ad := range append(ads, watch...) {
 		addrs = append(addrs, ad.String())
 	}
 	return
@@ -141,7 +261,7 @@ func (w *Wallet) AddrList() (addrs []string, err error) {
 
 //HasAddr ...
 func (w *Wallet) HasAddr(addr string) (has bool) {
-	ad, err := address.NewFromString(addr)
+	ad, err := parseAddress(addr)
 	if err != nil {
 		return false
 	}
@@ -151,7 +271,15 @@ func (w *Wallet) HasAddr(addr string) (has bool) {
 
 //Export ...
 func (w *Wallet) Export(addr string) (privateKey string, err error) {
-	ad, err := address.NewFromString(addr)
+	return w.ExportWithFormat(addr, KeyFormatHexLotus)
+}
+
+//ExportWithFormat format:hex-lotus,json-lotus,gfc-json,raw
+func (w *Wallet) ExportWithFormat(addr string, format string) (privateKey string, err error) {
+	if err = w.checkUnlocked(); err != nil {
+		return
+	}
+	ad, err := parseAddress(addr)
 	if err != nil {
 		return
 	}
@@ -160,42 +288,103 @@ func (w *Wallet) Export(addr string) (privateKey string, err error) {
 		return
 	}
 	if !has {
+		if w.epikWallet.WalletIsWatch(context.Background(), ad) {
+			return privateKey, ErrWatchOnly
+		}
 		return privateKey, fmt.Errorf("addr not found")
 	}
 	keyInfo, err := w.epikWallet.WalletExport(context.Background(), ad)
 	if err != nil {
 		return
 	}
-	data, err := json.Marshal(keyInfo)
+	return formatKeyInfo(keyInfo, format)
+}
+
+//Import any of the ExportWithFormat formats, detected automatically
+func (w *Wallet) Import(privateKey string) (addr string, err error) {
+	keyInfo, err := parseKeyInfo(privateKey)
 	if err != nil {
 		return "", err
 	}
-	privateKey = hex.EncodeToString(data)
-	return
+	ad, err := w.epikWallet.WalletImport(context.Background(), keyInfo)
+	if err != nil {
+		return "", err
+	}
+	return ad.String(), nil
 }
 
-//Import ...
-func (w *Wallet) Import(privateKey string) (addr string, err error) {
+//Delete moves the key to the trash, the default moves to the next address
+func (w *Wallet) Delete(addr string) (err error) {
+	if err = w.checkUnlocked(); err != nil {
+		return
+	}
+	ad, err := parseAddress(addr)
+	if err != nil {
+		return
+	}
+	has, err := w.epikWallet.WalletHas(context.Background(), ad)
+	if err != nil {
+		return
+	}
+	if !has {
+		return fmt.Errorf("addr not found")
+	}
+	err = w.epikWallet.WalletDelete(context.Background(), ad)
+	if err != nil {
+		return
+	}
+	// picks and stores a new default when the deleted key was the default
+	_, _ = w.epikWallet.GetDefault(context.Background())
+	return nil
+}
 
-	data, err := hex.DecodeString(privateKey)
+//ListTrash deleted addresses that can be restored
+func (w *Wallet) ListTrash() (listJSON string, err error) {
+	ads, err := w.epikWallet.WalletListTrash(context.Background())
 	if err != nil {
-		return "", err
+		return
+	}
+	list := []string{}
+	for _, ad := range ads {
+		list = append(list, ad.String())
 	}
-	keyInfo := &types.KeyInfo{}
-	err = json.Unmarshal(data, keyInfo)
+	data, err := json.Marshal(list)
 	if err != nil {
 		return "", err
 	}
-	ad, err := w.epikWallet.WalletImport(context.Background(), keyInfo)
+	return string(data), nil
+}
+
+//RestoreFromTrash ...
+func (w *Wallet) RestoreFromTrash(addr string) (err error) {
+	ad, err := parseAddress(addr)
 	if err != nil {
-		return "", err
+		return
 	}
-	return ad.String(), nil
+	err = w.epikWallet.WalletRestore(context.Background(), ad)
+	if err != nil {
+		return
+	}
+	// the restored key becomes the default if the wallet was empty
+	_, _ = w.epikWallet.GetDefault(context.Background())
+	return nil
+}
+
+//PurgeTrash deletes a trashed key for good
+func (w *Wallet) PurgeTrash(addr string) (err error) {
+	if err = w.checkUnlocked(); err != nil {
+		return
+	}
+	ad, err := parseAddress(addr)
+	if err != nil {
+		return
+	}
+	return w.epikWallet.WalletPurge(context.Background(), ad)
 }
 
 //SetDefault ...
 func (w *Wallet) SetDefault(addr string) (err error) {
-	ad, err := address.NewFromString(addr)
+	ad, err := parseAddress(addr)
 	if err != nil {
 		return err
 	}
@@ -204,9 +393,12 @@ func (w *Wallet) SetDefault(addr string) (err error) {
 
 //Sign ...
 func (w *Wallet) Sign(addr string, hash []byte) (signature []byte, err error) {
+	if err = w.checkUnlocked(); err != nil {
+		return
+	}
 	ad, err := w.epikWallet.GetDefault(context.Background())
 	if addr != "" {
-		ad, err = address.NewFromString(addr)
+		ad, err = parseAddress(addr)
 	}
 	if err != nil {
 		return
@@ -218,17 +410,58 @@ func (w *Wallet) Sign(addr string, hash []byte) (signature []byte, err error) {
 	return sign.MarshalBinary()
 }
 
+//Verify checks a signature produced by Sign or SignCID (crypto.Signature binary format)
+func (w *Wallet) Verify(addr string, msg []byte, signature []byte) (valid bool, err error) {
+	ad, err := parseAddress(addr)
+	if err != nil {
+		return
+	}
+	sign := &crypto.Signature{}
+	err = sign.UnmarshalBinary(signature)
+	if err != nil {
+		return
+	}
+	if ad.Protocol() == address.ID {
+		// signatures are made by key addresses, ask the chain which one this is
+		fullAPI, closer, err := w.fullAPI()
+		if err != nil {
+			return false, err
+		}
+		defer closer()
+		ad, err = fullAPI.StateAccountKey(context.Background(), ad, types.EmptyTSK)
+		if err != nil {
+			return false, err
+		}
+	}
+	switch ad.Protocol() {
+	case address.BLS:
+		if sign.Type != crypto.SigTypeBLS {
+			return false, nil
+		}
+	case address.SECP256K1:
+		if sign.Type != crypto.SigTypeSecp256k1 {
+			return false, nil
+		}
+	default:
+		return false, fmt.Errorf("address can't sign: %s", addr)
+	}
+	return sigs.Verify(sign, ad, msg) == nil, nil
+}
+
 func (w *Wallet) SignAndSendMessage(addr string, message string) (cidStr string, err error) {
 	msg := &types.Message{}
 	err = json.Unmarshal([]byte(message), msg)
 	if err != nil {
 		return
 	}
+	if err = checkMessageNetwork([]byte(message)); err != nil {
+		return
+	}
 	fromAddr, err := w.epikWallet.GetDefault(context.Background())
 	if err != nil {
 		return "", err
 	}
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -242,9 +475,12 @@ func (w *Wallet) SignAndSendMessage(addr string, message string) (cidStr string,
 }
 
 func (w *Wallet) SignCID(addr string, cidStr string) (signature []byte, err error) {
+	if err = w.checkUnlocked(); err != nil {
+		return
+	}
 	ad, err := w.epikWallet.GetDefault(context.Background())
 	if addr != "" {
-		ad, err = address.NewFromString(addr)
+		ad, err = parseAddress(addr)
 	}
 	if err != nil {
 		return
@@ -270,8 +506,8 @@ func (w *Wallet) SetRPC(url string, token string) (err error) {
 
 //Balance ...
 func (w *Wallet) Balance(addr string) (balance string, err error) {
-	ad, err := address.NewFromString(addr)
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	ad, err := parseAddress(addr)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return "", err
 	}
@@ -290,11 +526,11 @@ func (w *Wallet) Send(to string, amount string) (cidStr string, err error) {
 	if err != nil {
 		return "", err
 	}
-	toAddr, err := address.NewFromString(to)
+	toAddr, err := parseAddress(to)
 	if err != nil {
 		return
 	}
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -320,12 +556,13 @@ func (w *Wallet) Send(to string, amount string) (cidStr string, err error) {
 	return c.String(), nil
 }
 
+//CreateSendMessage unsigned transfer from the default address with nonce and gas filled, sign it with SignMessage
 func (w *Wallet) CreateSendMessage(to string, amount string) (message string, err error) {
 	fromAddr, err := w.epikWallet.GetDefault(context.Background())
 	if err != nil {
 		return "", err
 	}
-	toAddr, err := address.NewFromString(to)
+	toAddr, err := parseAddress(to)
 	if err != nil {
 		return
 	}
@@ -339,6 +576,14 @@ func (w *Wallet) CreateSendMessage(to string, amount string) (message string, er
 		To:    toAddr,
 		Value: types.BigInt(epk),
 	}
+	node, closer, err := w.fullAPI()
+	if err != nil {
+		return
+	}
+	defer closer()
+	if err = w.fillMessage(node, msg); err != nil {
+		return
+	}
 	data, _ := json.Marshal(msg)
 	message = string(data)
 	return
@@ -357,7 +602,7 @@ func (w *Wallet) GasEstimateGasLimit(actor string) (gasLimit string, err error)
 	default:
 		return "0", fmt.Errorf("actor not found")
 	}
-	node, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	node, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -383,51 +628,31 @@ func (w *Wallet) MessageCID(message string) (cidStr string, err error) {
 	return
 }
 
+//SendRawMessage pushes message with a signature made over it, nothing in message is changed
 func (w *Wallet) SendRawMessage(message string, signature []byte) (cidStr string, err error) {
 	msg := types.Message{}
 	err = json.Unmarshal([]byte(message), &msg)
 	if err != nil {
 		return
 	}
-	sign := &crypto.Signature{}
-	err = sign.UnmarshalBinary(signature)
-	if err != nil {
-		return
-	}
-	node, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
-	if err != nil {
-		return
-	}
-	defer closer()
-
-	msg.Nonce, err = node.MpoolGetNonce(context.Background(), msg.From)
-	if err != nil {
+	if err = checkMessageNetwork([]byte(message)); err != nil {
 		return
 	}
-	msg.GasFeeCap, err = node.GasEstimateFeeCap(context.Background(), &msg, 0, types.EmptyTSK)
-	if err != nil {
-		return
-	}
-	msg.GasLimit, err = node.GasEstimateGasLimit(context.Background(), &msg, types.EmptyTSK)
+	sign := &crypto.Signature{}
+	err = sign.UnmarshalBinary(signature)
 	if err != nil {
 		return
 	}
-	signedMsg := &types.SignedMessage{
+	return w.pushSignedMessage(&types.SignedMessage{
 		Message:   msg,
 		Signature: *sign,
-	}
-	fmt.Println(json.Marshal(signedMsg))
-	c, err := node.MpoolPush(context.Background(), signedMsg)
-	if err != nil {
-		return
-	}
-	return c.String(), nil
+	})
 }
 
 //MessageReceipt ...
 func (w *Wallet) MessageReceipt(cidStr string) (status string, err error) {
 	cidHash, err := cid.Parse(cidStr)
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -457,20 +682,21 @@ func (w *Wallet) MessageReceipt(cidStr string) (status string, err error) {
 }
 
 func (w *Wallet) sendMessage(fullAPI api.FullNode, msg *types.Message) (cidStr cid.Cid, err error) {
-	msg.Nonce, err = fullAPI.MpoolGetNonce(context.Background(), msg.From)
-	if err != nil {
+	if err = w.checkUnlocked(); err != nil {
 		return
 	}
-
-	msg.GasFeeCap, err = fullAPI.GasEstimateFeeCap(context.Background(), msg, 20, types.EmptyTSK)
-	if err != nil {
-		return
+	// before the node is asked for a nonce that would only be given back
+	if w.epikWallet.WalletIsWatch(context.Background(), msg.From) {
+		return cid.Undef, ErrWatchOnly
 	}
-	msg.GasLimit, err = fullAPI.GasEstimateGasLimit(context.Background(), msg, types.EmptyTSK)
-	if err != nil {
+	if err = w.fillMessage(fullAPI, msg); err != nil {
 		return
 	}
-	msg.GasLimit = int64(float64(msg.GasLimit) * 1.25)
+	defer func() {
+		if err != nil {
+			w.nonces.release(msg.From, msg.Nonce)
+		}
+	}()
 	signature, err := w.epikWallet.WalletSign(context.Background(), msg.From, msg.Cid().Bytes())
 	if err != nil {
 		return cid.Undef, err
@@ -482,11 +708,40 @@ func (w *Wallet) sendMessage(fullAPI api.FullNode, msg *types.Message) (cidStr c
 	return fullAPI.MpoolPush(context.Background(), signedMsg)
 }
 
+// fillMessage sets the nonce and gas of msg. The nonce is reserved, so
+// messages prepared back to back never share one; pushing through this wallet
+// confirms it, an abandoned one returns to the node after nonceTTL.
+func (w *Wallet) fillMessage(fullAPI api.FullNode, msg *types.Message) (err error) {
+	nodeNonce, err := fullAPI.MpoolGetNonce(context.Background(), msg.From)
+	if err != nil {
+		return
+	}
+	msg.Nonce = w.nonces.reserve(msg.From, nodeNonce)
+	if err = fillGas(fullAPI, msg); err != nil {
+		w.nonces.release(msg.From, msg.Nonce)
+	}
+	return
+}
+
+// fillGas sets the gas of msg from the node
+func fillGas(fullAPI api.FullNode, msg *types.Message) (err error) {
+	msg.GasFeeCap, err = fullAPI.GasEstimateFeeCap(context.Background(), msg, 20, types.EmptyTSK)
+	if err != nil {
+		return
+	}
+	msg.GasLimit, err = fullAPI.GasEstimateGasLimit(context.Background(), msg, types.EmptyTSK)
+	if err != nil {
+		return
+	}
+	msg.GasLimit = int64(float64(msg.GasLimit) * 1.25)
+	return nil
+}
+
 func (w *Wallet) CoinbaseInfo(addr string) (infoJSON string, err error) {
 	ctx := context.Background()
 	from := address.Address{}
 	if addr != "" {
-		from, err = address.NewFromString(addr)
+		from, err = parseAddress(addr)
 		if err != nil {
 			return
 		}
@@ -499,7 +754,7 @@ func (w *Wallet) CoinbaseInfo(addr string) (infoJSON string, err error) {
 	if from.Empty() {
 		return "", fmt.Errorf("no address")
 	}
-	node, closer, err := client.NewFullNodeRPC(ctx, w.rpcURL, w.header)
+	node, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -538,7 +793,7 @@ func (w *Wallet) CoinbaseWithdraw() (cidStr string, err error) {
 		return
 	}
 
-	node, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	node, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -576,8 +831,11 @@ func (w *Wallet) CoinbaseWithdraw() (cidStr string, err error) {
 	return c.String(), nil
 }
 
+// dialFullNode is replaced by a stub node in tests
+var dialFullNode = client.NewFullNodeRPC
+
 func (w *Wallet) fullAPI() (fullAPI api.FullNode, closer jsonrpc.ClientCloser, err error) {
-	return client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	return dialFullNode(context.Background(), w.rpcURL, w.header)
 }
 
 //CreateExpert 创建领域专家
@@ -601,7 +859,7 @@ func (w *Wallet) CreateExpert(applicationHash string) (expertID string, err erro
 		Method: builtin.MethodsExpertFunds.ApplyForExpert,
 		Params: params,
 	}
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -629,11 +887,11 @@ func (w *Wallet) ExpertNominate(_expert, target string) (cID string, err error)
 	fmt.Println("Nominate expert message")
 
 	owner, err := w.epikWallet.GetDefault(context.Background())
-	targetAddr, err := address.NewFromString(target)
+	targetAddr, err := parseAddress(target)
 	if err != nil {
 		return "", err
 	}
-	expertAddr, err := address.NewFromString(_expert)
+	expertAddr, err := parseAddress(_expert)
 	if err != nil {
 		return "", err
 	}
@@ -649,7 +907,7 @@ func (w *Wallet) ExpertNominate(_expert, target string) (cID string, err error)
 		Method: expert.Methods.Nominate,
 		Params: params,
 	}
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -664,11 +922,11 @@ func (w *Wallet) ExpertNominate(_expert, target string) (cID string, err error)
 
 //ExpertInfo 专家信息
 func (w *Wallet) ExpertInfo(addr string) (infoJSON string, err error) {
-	expertAddr, err := address.NewFromString(addr)
+	expertAddr, err := parseAddress(addr)
 	if err != nil {
 		return
 	}
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -686,7 +944,7 @@ func (w *Wallet) ExpertInfo(addr string) (infoJSON string, err error) {
 
 //ExpertList ...
 func (w *Wallet) ExpertList() (listJSON string, err error) {
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -704,7 +962,7 @@ func (w *Wallet) ExpertList() (listJSON string, err error) {
 
 //VoteSend 投票
 func (w *Wallet) VoteSend(candidate string, amount string) (cidStr string, err error) {
-	candidateAddr, err := address.NewFromString(candidate)
+	candidateAddr, err := parseAddress(candidate)
 	if err != nil {
 		return
 	}
@@ -717,7 +975,7 @@ func (w *Wallet) VoteSend(candidate string, amount string) (cidStr string, err e
 		return
 	}
 	from, err := w.epikWallet.GetDefault(context.Background())
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -739,7 +997,7 @@ func (w *Wallet) VoteSend(candidate string, amount string) (cidStr string, err e
 
 //VoteRescind 撤销
 func (w *Wallet) VoteRescind(candidate string, amount string) (cidStr string, err error) {
-	candidateAddr, err := address.NewFromString(candidate)
+	candidateAddr, err := parseAddress(candidate)
 	if err != nil {
 		return
 	}
@@ -756,7 +1014,7 @@ func (w *Wallet) VoteRescind(candidate string, amount string) (cidStr string, er
 		return
 	}
 	from, err := w.epikWallet.GetDefault(context.Background())
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -783,7 +1041,7 @@ func (w *Wallet) VoteWithdraw(to string) (cidStr string, err error) {
 	if to == "" {
 		toAddr, err = w.epikWallet.GetDefault(context.Background())
 	} else {
-		toAddr, err = address.NewFromString(to)
+		toAddr, err = parseAddress(to)
 	}
 	if err != nil {
 		return
@@ -794,7 +1052,7 @@ func (w *Wallet) VoteWithdraw(to string) (cidStr string, err error) {
 		return "", fmt.Errorf("serializing params: %w", err)
 	}
 	from, err := w.epikWallet.GetDefault(context.Background())
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -816,11 +1074,11 @@ func (w *Wallet) VoteWithdraw(to string) (cidStr string, err error) {
 
 //VoterInfo 投票信息
 func (w *Wallet) VoterInfo(addr string) (infoJSON string, err error) {
-	ad, err := address.NewFromString(addr)
+	ad, err := parseAddress(addr)
 	if err != nil {
 		return
 	}
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -842,12 +1100,12 @@ func (w *Wallet) MinerInfo(minerID string) (infoJSON string, err error) {
 			err = fmt.Errorf("crashed:%+v", err)
 		}
 	}()
-	minerAddr, err := address.NewFromString(minerID)
+	minerAddr, err := parseAddress(minerID)
 	if err != nil {
 		return
 	}
 	ctx := context.Background()
-	fullAPI, closer, err := client.NewFullNodeRPC(ctx, w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -957,7 +1215,7 @@ func (w *Wallet) MinerPledgeAdd(toMinerID string, amount string) (cidStr string,
 	if toMinerID == "" {
 		return "", fmt.Errorf("toMinerID is empty")
 	}
-	toAddr, err = address.NewFromString(toMinerID)
+	toAddr, err = parseAddress(toMinerID)
 	if err != nil {
 		return
 	}
@@ -969,7 +1227,7 @@ func (w *Wallet) MinerPledgeAdd(toMinerID string, amount string) (cidStr string,
 	if err != nil {
 		return "", err
 	}
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1009,7 +1267,7 @@ func (w *Wallet) MinerPledgeOneClick(minerStr string) (err error) {
 	if err != nil {
 		return err
 	}
-	node, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	node, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1022,7 +1280,7 @@ func (w *Wallet) MinerPledgeOneClick(minerStr string) (err error) {
 		return fmt.Errorf("not enough balance")
 	}
 	for _, minerID := range minerIDs {
-		m, err := address.NewFromString(minerID)
+		m, err := parseAddress(minerID)
 		if err != nil {
 			return err
 		}
@@ -1052,7 +1310,7 @@ func (w *Wallet) MinerPledgeApplyWithdrawOneClick(minerStr string) (err error) {
 	if err != nil {
 		return err
 	}
-	node, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	node, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1062,7 +1320,7 @@ func (w *Wallet) MinerPledgeApplyWithdrawOneClick(minerStr string) (err error) {
 		return
 	}
 	for _, minerID := range minerIDs {
-		m, err := address.NewFromString(minerID)
+		m, err := parseAddress(minerID)
 		if err != nil {
 			continue
 		}
@@ -1106,7 +1364,7 @@ func (w *Wallet) MinerPledgeWithdrawOneClick(minerStr string) (err error) {
 	if err != nil {
 		return err
 	}
-	node, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	node, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1120,7 +1378,7 @@ func (w *Wallet) MinerPledgeWithdrawOneClick(minerStr string) (err error) {
 		return
 	}
 	for _, minerID := range minerIDs {
-		m, err := address.NewFromString(minerID)
+		m, err := parseAddress(minerID)
 		if err != nil {
 			continue
 		}
@@ -1165,7 +1423,7 @@ func (w *Wallet) MinerPledgeWithdraw(toMinerID string, amount string) (cidStr st
 	if toMinerID == "" {
 		return "", fmt.Errorf("toMinerID is empty")
 	}
-	toAddr, err = address.NewFromString(toMinerID)
+	toAddr, err = parseAddress(toMinerID)
 	if err != nil {
 		return
 	}
@@ -1174,7 +1432,7 @@ func (w *Wallet) MinerPledgeWithdraw(toMinerID string, amount string) (cidStr st
 		return "", err
 	}
 	from, err := w.epikWallet.GetDefault(context.Background())
-	node, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	node, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1226,11 +1484,11 @@ func (w *Wallet) MinerPledgeWithdraw(toMinerID string, amount string) (cidStr st
 }
 
 func (w *Wallet) MinerPledgeApplyWithdraw(minerID string) (cidStr string, err error) {
-	minerAddr, err := address.NewFromString(minerID)
+	minerAddr, err := parseAddress(minerID)
 	if err != nil {
 		return
 	}
-	node, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	node, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1272,11 +1530,11 @@ func (w *Wallet) MinerPledgeApplyWithdraw(minerID string) (cidStr string, err er
 }
 
 func (w *Wallet) MinerPledgeTransfer(fromMinerID, toMinerID string, amount string) (cidStr string, err error) {
-	fromMiner, err := address.NewFromString(fromMinerID)
+	fromMiner, err := parseAddress(fromMinerID)
 	if err != nil {
 		return
 	}
-	toMiner, err := address.NewFromString(toMinerID)
+	toMiner, err := parseAddress(toMinerID)
 	if err != nil {
 		return
 	}
@@ -1284,7 +1542,7 @@ func (w *Wallet) MinerPledgeTransfer(fromMinerID, toMinerID string, amount strin
 	if err != nil {
 		return "", err
 	}
-	node, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	node, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1316,12 +1574,12 @@ func (w *Wallet) MinerPledgeTransfer(fromMinerID, toMinerID string, amount strin
 }
 
 func (w *Wallet) RetrievePledgeState(addr string) (stateJSON string, err error) {
-	target, err := address.NewFromString(addr)
+	target, err := parseAddress(addr)
 	if err != nil {
 		return
 	}
 
-	node, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	node, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1349,7 +1607,7 @@ func (w *Wallet) RetrievePledgeAdd(target string, miner string, amount string) (
 	if target == "" {
 		return "", fmt.Errorf("target is empty")
 	}
-	targetAddr, err = address.NewFromString(target)
+	targetAddr, err = parseAddress(target)
 	if err != nil {
 		return
 	}
@@ -1358,7 +1616,7 @@ func (w *Wallet) RetrievePledgeAdd(target string, miner string, amount string) (
 	if err != nil {
 		return "", err
 	}
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1369,7 +1627,7 @@ func (w *Wallet) RetrievePledgeAdd(target string, miner string, amount string) (
 			return
 		}
 	}
-	minerAddr, err = address.NewFromString(miner)
+	minerAddr, err = parseAddress(miner)
 	var params []byte
 	if err == nil && !minerAddr.Empty() {
 		params, err = actors.SerializeParams(&retrieval.PledgeParams{
@@ -1414,7 +1672,7 @@ func (w *Wallet) RetrievePledgeBind(miner string, amount string) (cidStr string,
 	if miner == "" {
 		return "", fmt.Errorf("target is empty")
 	}
-	minerAddr, err = address.NewFromString(miner)
+	minerAddr, err = parseAddress(miner)
 	if err != nil {
 		return
 	}
@@ -1434,7 +1692,7 @@ func (w *Wallet) RetrievePledgeBind(miner string, amount string) (cidStr string,
 		return "", xerrors.Errorf("serializing params failed: %w", err)
 	}
 
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1466,7 +1724,7 @@ func (w *Wallet) RetrievePledgeUnBind(miner string, amount string) (cidStr strin
 	if miner == "" {
 		return "", fmt.Errorf("target is empty")
 	}
-	minerAddr, err = address.NewFromString(miner)
+	minerAddr, err = parseAddress(miner)
 	if err != nil {
 		return
 	}
@@ -1486,7 +1744,7 @@ func (w *Wallet) RetrievePledgeUnBind(miner string, amount string) (cidStr strin
 		return "", xerrors.Errorf("serializing params failed: %w", err)
 	}
 
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1516,7 +1774,7 @@ func (w *Wallet) RetrievePledgeApplyWithdraw(target string, amount string) (cidS
 	if target == "" {
 		return "", fmt.Errorf("toMinerID is empty")
 	}
-	targetAddr, err := address.NewFromString(target)
+	targetAddr, err := parseAddress(target)
 	if err != nil {
 		return
 	}
@@ -1525,7 +1783,7 @@ func (w *Wallet) RetrievePledgeApplyWithdraw(target string, amount string) (cidS
 		return "", err
 	}
 
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -1576,7 +1834,7 @@ func (w *Wallet) RetrievePledgeWithdraw(amount string) (cidStr string, err error
 		return "", xerrors.Errorf("serializing params failed: %w", err)
 	}
 
-	fullAPI, closer, err := client.NewFullNodeRPC(context.Background(), w.rpcURL, w.header)
+	fullAPI, closer, err := w.fullAPI()
 	if err != nil {
 		return
 	}
@@ -0,0 +1,764 @@
+package epik
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/airgap"
+	"github.com/EpiK-Protocol/epik-wallet-golib/epik/wallet"
+	"github.com/EpiK-Protocol/epik-wallet-golib/hd"
+	"github.com/EpiK-Protocol/go-epik/api"
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+	"github.com/EpiK-Protocol/go-epik/lib/sigs"
+	"github.com/filecoin-project/go-address"
+	jsonrpc "github.com/filecoin-project/go-jsonrpc"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/xerrors"
+)
+
+func TestNewKey(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("epik wallet")
+	for _, typ := range []string{"bls", "secp256k1"} {
+		addr, err := w.NewKey(typ)
+		if err != nil {
+			t.Fatalf("%s: %s", typ, err)
+		}
+		other, err := w.NewKey(typ)
+		if err != nil {
+			t.Fatalf("%s: %s", typ, err)
+		}
+		if addr == other {
+			t.Fatalf("%s: random keys collide: %s", typ, addr)
+		}
+
+		data, err := w.Sign(addr, msg)
+		if err != nil {
+			t.Fatalf("%s: %s", typ, err)
+		}
+		sig := &crypto.Signature{}
+		if err := sig.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		a, err := address.NewFromString(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sigs.Verify(sig, a, msg); err != nil {
+			t.Fatalf("%s: %s", typ, err)
+		}
+		o, err := address.NewFromString(other)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sigs.Verify(sig, o, msg); err == nil {
+			t.Fatalf("%s: signature verified against the wrong key", typ)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("epik wallet")
+	for _, typ := range []string{"bls", "secp256k1"} {
+		addr, err := w.NewKey(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := w.Sign(addr, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		valid, err := w.Verify(addr, msg, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.Fatalf("%s: valid signature rejected", typ)
+		}
+		valid, err = w.Verify(addr, []byte("other"), sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if valid {
+			t.Fatalf("%s: signature accepted for another message", typ)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signs := []string{}
+	items := []map[string]string{}
+	for i := 0; i < 3; i++ {
+		addr, err := w.NewKey("bls")
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := []byte(fmt.Sprintf("record %d", i))
+		sig, err := w.Sign(addr, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signs = append(signs, hex.EncodeToString(sig))
+		items = append(items, map[string]string{"address": addr, "message": hex.EncodeToString(msg)})
+	}
+	signsJSON, _ := json.Marshal(signs)
+	agg, err := w.AggregateSignatures(string(signsJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	itemsJSON, _ := json.Marshal(items)
+	valid, err := w.VerifyAggregate(agg, string(itemsJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Fatal("aggregate signature rejected")
+	}
+
+	items[0]["message"] = hex.EncodeToString([]byte("forged"))
+	itemsJSON, _ = json.Marshal(items)
+	valid, err = w.VerifyAggregate(agg, string(itemsJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid {
+		t.Fatal("aggregate signature accepted for a changed message")
+	}
+}
+
+func TestTrash(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := w.NewKey("secp256k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := w.NewKey("secp256k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetDefault(first); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Delete(first); err != nil {
+		t.Fatal(err)
+	}
+	if w.HasAddr(first) {
+		t.Fatal("deleted key still listed")
+	}
+	def, err := w.epikWallet.GetDefault(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.String() != second {
+		t.Fatalf("default not reassigned: %s", def)
+	}
+	trash, err := w.ListTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trash != fmt.Sprintf(`["%s"]`, first) {
+		t.Fatalf("unexpected trash: %s", trash)
+	}
+
+	if err := w.RestoreFromTrash(first); err != nil {
+		t.Fatal(err)
+	}
+	if !w.HasAddr(first) {
+		t.Fatal("restored key missing")
+	}
+	if trash, _ = w.ListTrash(); trash != "[]" {
+		t.Fatalf("trash not emptied: %s", trash)
+	}
+
+	if err := w.Delete(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	// the LocalWallet refuses on its own, not only behind the facade
+	secondAddr, _ := address.NewFromString(second)
+	if err := w.epikWallet.WalletPurge(context.Background(), secondAddr); !xerrors.Is(err, ErrWalletLocked) {
+		t.Fatalf("WalletPurge while locked: %v", err)
+	}
+	if err := w.Unlock(""); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.PurgeTrash(second); err != nil {
+		t.Fatal(err)
+	}
+	if trash, _ = w.ListTrash(); trash != "[]" {
+		t.Fatalf("trash not purged: %s", trash)
+	}
+}
+
+func TestKeyFormats(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, typ := range []string{"bls", "secp256k1"} {
+		addr, err := w.NewKey(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, format := range []string{KeyFormatHexLotus, KeyFormatJSONLotus, KeyFormatGfcJSON, KeyFormatRaw} {
+			exported, err := w.ExportWithFormat(addr, format)
+			if err != nil {
+				t.Fatalf("%s %s: %s", typ, format, err)
+			}
+			other, err := NewWallet()
+			if err != nil {
+				t.Fatal(err)
+			}
+			imported, err := other.Import(exported)
+			if err != nil {
+				t.Fatalf("%s %s: %s", typ, format, err)
+			}
+			if imported != addr {
+				t.Fatalf("%s %s: imported %s, want %s", typ, format, imported, addr)
+			}
+		}
+	}
+}
+
+// frozen derivation vectors, existing users' addresses depend on them and must never change
+var hdPathVectors = []struct {
+	mnemonic  string
+	path      string
+	key       string // hex of the BIP32 private key at path, the seed of the legacy keys
+	bls       string // legacy bls address without the network prefix
+	bip32Secp string // KeySchemeBIP32 secp256k1 address without the network prefix
+}{
+	{
+		mnemonic:  "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		path:      "m/44'/196'/1'/0/0",
+		key:       "e66d7bb3795302a43d1e9724bfbac1d5d68d7c16f20c74f56a2b8135b57a2c97",
+		bls:       "3tacbcx5duia6ljgxvzfgfm6x52kh7quuqoau36ubvlnepmrtxspan2pu3gtumsitsnmz4mptqtseie22bc3a",
+		bip32Secp: "1sfbh2cpviuup4mfaseknuruqz2i3tydsf6fpo5q",
+	},
+	{
+		mnemonic:  "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		path:      "m/44'/196'/1'/0/1",
+		key:       "555c8aea3495b4428eba6d7ccbe092e0dc0d7b030d7ee0850a419753146b1bb5",
+		bls:       "3qpgsdimkarpukydyj5nrbo35qsrrbycb4iwigxp2jwxn3mdmph6rkujxqazqof73b3fatmcp744drjemktva",
+		bip32Secp: "1lhndnvpwwwihjuuzqcld3xxjgcsgwkod24cjsvq",
+	},
+	{
+		mnemonic:  "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		path:      "m/44'/196'/0'/0/0",
+		key:       "d9618956ad760e0a88a0b1575f0dd9c337a4a2e9946c6fd7faed2df1a204b4fd",
+		bls:       "3ughd54w57qkv46nzewwqzqzldfiqnmocel5zc4q4zznqqvzrrah45veviwsezjoe3dtmtvl3seh4szgpfdba",
+		bip32Secp: "1fpueeyuzwyliapejte5dz3kjrvd66quppx3ct5q",
+	},
+	{
+		mnemonic:  "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		path:      "m/44'/196'/1'/0/0",
+		key:       "102364cd8587060acfe9bfd3dd78e8ada6fe320ea68290468d0cb3610447bb8b",
+		bls:       "3wuvqbea4x27svtuwjps23m6uldstg3i37zehr3rylsatcouoroupmobnn4ir4cifyzzppls5yangxiy2azaa",
+		bip32Secp: "1j7n2cxrwmnzjanssnvp4ooaxuclj2gsyz6imcxi",
+	},
+	{
+		mnemonic:  "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		path:      "m/44'/196'/1'/0/1",
+		key:       "df8990763bfcb8a114cbd85bb9821beadf488f0b89e326cb9a8761121e0171cf",
+		bls:       "3sp4zl63jpsvzpvbbm7gnk66dui63b7vgmqebpvdapiccajsvtf6nixgr5z3vkymaydoh5apqekwtidqhdkxq",
+		bip32Secp: "1qr7yipzduqkjwip2uuaf5ru32vhwaqj6uga7qwq",
+	},
+	{
+		mnemonic:  "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		path:      "m/44'/196'/0'/0/0",
+		key:       "342bcc83483f0b6647f53ced6e710fd9e663df655f2f24588a4a42a5a5049a9f",
+		bls:       "3s5nafqurbrmficbloaltnsukvojnmpu4ferx6prisvj3jvpqceq3hw5k75btkfjptazavnkjbxr7yy2g5h6q",
+		bip32Secp: "1mjdtmhhiliark2mmhze4vzckwjc44gulkv55l4i",
+	},
+	{
+		// the child key starts with a zero byte
+		mnemonic:  "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		path:      "m/44'/196'/1'/0/14",
+		key:       "006c682c87f57da18b420a053c29933aee13effa27bd98cd65f4586d46e3381e",
+		bls:       "3w7fomfzfdbmosijrerne46truuturw2afhzju3dp5idulik72i3xorvjon64emusli5fivzud5vnptrw2jva",
+		bip32Secp: "1l34uo5wtn6vdxcgcfw7hvpqipxo5er5ryy2wi7i",
+	},
+}
+
+func TestHDPathVectors(t *testing.T) {
+	for _, v := range hdPathVectors {
+		seed := bip39.NewSeed(v.mnemonic, "")
+		key, err := epikHDPathSeed(seed, v.path)
+		if err != nil {
+			t.Fatalf("%s: %s", v.path, err)
+		}
+		if hex.EncodeToString(key) != v.key {
+			t.Fatalf("%s: key %x, want %s", v.path, key, v.key)
+		}
+
+		w, err := NewWallet()
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := w.GenerateKey("bls", seed, v.path)
+		if err != nil {
+			t.Fatalf("%s: %s", v.path, err)
+		}
+		if addr[1:] != v.bls {
+			t.Fatalf("%s: bls address %s, want ?%s", v.path, addr, v.bls)
+		}
+
+		bip32, err := w.GenerateKeyWithScheme("secp256k1", seed, v.path, KeySchemeBIP32)
+		if err != nil {
+			t.Fatalf("%s: %s", v.path, err)
+		}
+		if bip32[1:] != v.bip32Secp {
+			t.Fatalf("%s: bip32 secp256k1 address %s, want ?%s", v.path, bip32, v.bip32Secp)
+		}
+
+		// legacy secp256k1 keys are the go-epik seed key generation fed with
+		// the frozen path key, nothing between the mnemonic and that call may change
+		frozen, err := hex.DecodeString(v.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := wallet.GenerateKeyFromSeed(types.KTSecp256k1, frozen)
+		if err != nil {
+			t.Fatalf("%s: %s", v.path, err)
+		}
+		secp, err := w.GenerateKey("secp256k1", seed, v.path)
+		if err != nil {
+			t.Fatalf("%s: %s", v.path, err)
+		}
+		if secp != want.Address.String() {
+			t.Fatalf("%s: legacy secp256k1 address %s, want %s", v.path, secp, want.Address)
+		}
+	}
+}
+
+// the passphrase seeds of the BIP39 vectors in hd, frozen at the default epik path
+func TestPassphraseSeed(t *testing.T) {
+	for _, c := range []struct {
+		mnemonic   string
+		passphrase string
+		bls        string
+	}{
+		{
+			mnemonic:   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			passphrase: "TREZOR",
+			bls:        "3vn76vjx4j3ogag2kedzmh5fidsyjitlnpbkwtg6ee5w3pl4obl47r76325bujwqjqhemrpreid2asmflgy3q",
+		},
+		{
+			mnemonic:   "あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あおぞら",
+			passphrase: "㍍ガバヴァぱばぐゞちぢ十人十色",
+			bls:        "3rh7yj6sgavbf7bu2mz5xppf2urwdi77edgukbtd5s7soyfpinrkdh5pai7do4n5fmovuyw6m6tbkk2zuasza",
+		},
+	} {
+		seed, err := hd.SeedFromMnemonicWithPassphrase(c.mnemonic, c.passphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := NewWallet()
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := w.GenerateKey("bls", seed, "m/44'/196'/1'/0/0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr[1:] != c.bls {
+			t.Fatalf("%s: bls address %s, want ?%s", c.passphrase, addr, c.bls)
+		}
+	}
+}
+
+func TestGenerateKeyEIP2333(t *testing.T) {
+	seed := bip39.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.GenerateKeyWithScheme("bls", seed, "m/12381/461/0/0", KeySchemeEIP2333)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := w.GenerateKeyWithScheme("bls", seed, "m/44'/196'/1'/0/0", KeySchemeLegacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr == legacy {
+		t.Fatal("eip2333 and legacy schemes derived the same key")
+	}
+	if _, err := w.GenerateKeyWithScheme("secp256k1", seed, "m/12381/461/0/0", KeySchemeEIP2333); err == nil {
+		t.Fatal("eip2333 secp256k1 key accepted")
+	}
+}
+
+func TestGenerateKeyBIP32(t *testing.T) {
+	seed := bip39.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	path := "m/44'/461'/0'/0/0"
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.GenerateKeyWithScheme("secp256k1", seed, path, KeySchemeBIP32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := w.ExportWithFormat(addr, KeyFormatRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := epikHDPathSeed(seed, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported != "secp256k1:"+hex.EncodeToString(key) {
+		t.Fatalf("bip32 key is not the child key: %s", exported)
+	}
+	if _, err := w.GenerateKeyWithScheme("bls", seed, path, KeySchemeBIP32); err == nil {
+		t.Fatal("bip32 bls key accepted")
+	}
+}
+
+func TestWatchOnly(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := other.NewKey("secp256k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.AddWatchAddress(addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddWatchAddress("f01000"); err != nil {
+		t.Fatal(err)
+	}
+	if !w.IsWatchOnly(addr) {
+		t.Fatal("watch address not reported")
+	}
+	addrs, err := w.AddrList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 2 {
+		t.Fatalf("unexpected address list: %v", addrs)
+	}
+	if _, err := w.Sign(addr, []byte("epik wallet")); !xerrors.Is(err, ErrWatchOnly) {
+		t.Fatalf("expected ErrWatchOnly, got %v", err)
+	}
+	if _, err := w.Export(addr); !xerrors.Is(err, ErrWatchOnly) {
+		t.Fatalf("expected ErrWatchOnly, got %v", err)
+	}
+	// checked before the node is used
+	from, _ := address.NewFromString(addr)
+	if _, err := w.sendMessage(nil, &types.Message{From: from}); !xerrors.Is(err, ErrWatchOnly) {
+		t.Fatalf("sendMessage: expected ErrWatchOnly, got %v", err)
+	}
+
+	// the list stays readable while locked, like the hd one
+	if err := w.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if !w.IsWatchOnly(addr) {
+		t.Fatal("watch address lost on Lock")
+	}
+	if err := w.RemoveWatchAddress(addr); !xerrors.Is(err, ErrWalletLocked) {
+		t.Fatalf("RemoveWatchAddress while locked: %v", err)
+	}
+	if err := w.Unlock(""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.RemoveWatchAddress(addr); err != nil {
+		t.Fatal(err)
+	}
+	id, _ := address.NewFromString("f01000")
+	if list, _ := w.WatchList(); list != fmt.Sprintf(`["%s"]`, id) {
+		t.Fatalf("unexpected watch list: %s", list)
+	}
+}
+
+func TestXpub(t *testing.T) {
+	seed := bip39.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub, err := w.ExportXpub(seed, "m/44'/461'/0'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		addr, err := w.GenerateKeyWithScheme("secp256k1", seed, fmt.Sprintf("m/44'/461'/0'/0/%d", i), KeySchemeBIP32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		watch, err := XpubAddress(xpub, fmt.Sprintf("m/0/%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if watch != addr {
+			t.Fatalf("xpub address %s, want %s", watch, addr)
+		}
+	}
+	if _, err := XpubAddress(xpub, "m/0'/0"); err == nil {
+		t.Fatal("hardened derivation from xpub accepted")
+	}
+
+	watcher, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := watcher.AddWatchFromXpub(xpub, "m/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := watcher.Sign(addr, []byte("epik wallet")); !xerrors.Is(err, ErrWatchOnly) {
+		t.Fatalf("expected ErrWatchOnly, got %v", err)
+	}
+}
+
+func TestSetNetwork(t *testing.T) {
+	defer SetNetwork("")
+
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetNetwork(NetworkTestnet); err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.NewKey("secp256k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetNetwork(NetworkMainnet); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ValidateAddress(addr); !xerrors.Is(err, ErrWrongNetwork) {
+		t.Fatalf("expected ErrWrongNetwork, got %v", err)
+	}
+	addrs, err := w.AddrList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 1 || addrs[0] != "f"+addr[1:] {
+		t.Fatalf("unexpected address list: %v", addrs)
+	}
+	// keys stored under the testnet name are still found
+	if _, err := w.Sign(addrs[0], []byte("epik wallet")); err != nil {
+		t.Fatal(err)
+	}
+	// messages carry the prefix of their addresses until they are unmarshalled
+	message := fmt.Sprintf(`{"Version":0,"To":"%s","From":"%s","Nonce":3,"Value":"1000","GasLimit":600000,"GasFeeCap":"100","GasPremium":"100","Method":0,"Params":null}`, addr, addrs[0])
+	signedJSON := fmt.Sprintf(`{"Message":%s,"Signature":{"Type":1,"Data":""}}`, message)
+	for name, send := range map[string]func() error{
+		"PrepareMessage": func() error {
+			_, err := w.PrepareMessage(message)
+			return err
+		},
+		"SignMessage": func() error {
+			_, err := w.SignMessage(message)
+			return err
+		},
+		"BroadcastSignedMessage": func() error {
+			_, err := w.BroadcastSignedMessage(signedJSON)
+			return err
+		},
+		"SignAndSendMessage": func() error {
+			_, err := w.SignAndSendMessage("", message)
+			return err
+		},
+		"SendRawMessage": func() error {
+			_, err := w.SendRawMessage(message, nil)
+			return err
+		},
+	} {
+		if err := send(); !xerrors.Is(err, ErrWrongNetwork) {
+			t.Fatalf("%s: expected ErrWrongNetwork, got %v", name, err)
+		}
+	}
+
+	if err := AddNetwork("devnet", address.TestnetPrefix); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetNetwork("devnet"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ValidateAddress(addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetNetwork("unknown"); err == nil {
+		t.Fatal("unknown network accepted")
+	}
+
+	if err := SetNetwork(""); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ValidateAddress(addrs[0]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignMessage(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := w.NewKey("secp256k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := w.NewKey("bls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := fmt.Sprintf(`{"Version":0,"To":"%s","From":"%s","Nonce":3,"Value":"1000","GasLimit":600000,"GasFeeCap":"100","GasPremium":"100","Method":0,"Params":null}`, to, from)
+	signedJSON, err := w.SignMessage(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := &types.SignedMessage{}
+	if err := json.Unmarshal([]byte(signedJSON), signed); err != nil {
+		t.Fatal(err)
+	}
+	if signed.Message.Nonce != 3 || signed.Message.GasLimit != 600000 {
+		t.Fatalf("message changed while signing: %+v", signed.Message)
+	}
+	if err := sigs.Verify(&signed.Signature, signed.Message.From, signed.Message.Cid().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	unprepared := fmt.Sprintf(`{"Version":0,"To":"%s","From":"%s","Nonce":0,"Value":"1000","GasLimit":0,"GasFeeCap":"0","GasPremium":"0","Method":0,"Params":null}`, to, from)
+	if _, err := w.SignMessage(unprepared); err == nil {
+		t.Fatal("unprepared message signed")
+	}
+}
+
+func TestSignMessageEnvelope(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := w.NewKey("bls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := fmt.Sprintf(`{"Version":0,"To":"%s","From":"%s","Nonce":7,"Value":"1000","GasLimit":600000,"GasFeeCap":"100","GasPremium":"100","Method":0,"Params":null}`, from, from)
+	envelope, err := MessageEnvelope(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedEnvelope, err := w.SignMessageEnvelope(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.SignMessageEnvelope(signedEnvelope); err == nil {
+		t.Fatal("signed envelope signed again")
+	}
+	signedJSON, err := EnvelopeJSON(signedEnvelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := &types.SignedMessage{}
+	if err := json.Unmarshal([]byte(signedJSON), signed); err != nil {
+		t.Fatal(err)
+	}
+	if signed.Message.Nonce != 7 {
+		t.Fatalf("message changed in envelope: %+v", signed.Message)
+	}
+	if err := sigs.Verify(&signed.Signature, signed.Message.From, signed.Message.Cid().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBroadcastSignedEnvelope(t *testing.T) {
+	node := &stubNode{}
+	defer func(dial func(context.Context, string, http.Header) (api.FullNode, jsonrpc.ClientCloser, error)) {
+		dialFullNode = dial
+	}(dialFullNode)
+	dialFullNode = func(context.Context, string, http.Header) (api.FullNode, jsonrpc.ClientCloser, error) {
+		return node, func() {}, nil
+	}
+
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := w.NewKey("secp256k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := fmt.Sprintf(`{"Version":0,"To":"%s","From":"%s","Nonce":7,"Value":"1000","GasLimit":600000,"GasFeeCap":"100","GasPremium":"100","Method":0,"Params":null}`, from, from)
+	envelope, err := MessageEnvelope(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.BroadcastSignedEnvelope(envelope); err == nil {
+		t.Fatal("unsigned envelope pushed")
+	}
+	signedEnvelope, err := w.SignMessageEnvelope(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a message changed after signing is refused before reaching the node
+	payload, err := airgap.DecodeKind(signedEnvelope, airgap.KindEpiKSignedMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := types.DecodeSignedMessage(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := signed.Cid().String()
+	signed.Message.Value = types.NewInt(2000)
+	data, err := signed.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered, err := airgap.Encode(airgap.KindEpiKSignedMessage, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.BroadcastSignedEnvelope(tampered); err == nil {
+		t.Fatal("tampered envelope pushed")
+	}
+	if len(node.pushed) != 0 {
+		t.Fatalf("pushed %v", node.pushed)
+	}
+
+	cidStr, err := w.BroadcastSignedEnvelope(signedEnvelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cidStr != want || fmt.Sprint(node.pushed) != "[7]" {
+		t.Fatalf("pushed %v as %s", node.pushed, cidStr)
+	}
+}
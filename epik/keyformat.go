@@ -0,0 +1,125 @@
+package epik
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+)
+
+// key export formats
+const (
+	// hex of the KeyInfo JSON, what `epik wallet export` prints
+	KeyFormatHexLotus = "hex-lotus"
+	// KeyInfo JSON
+	KeyFormatJSONLotus = "json-lotus"
+	// go-filecoin wallet export JSON
+	KeyFormatGfcJSON = "gfc-json"
+	// <type>:<hex private key>
+	KeyFormatRaw = "raw"
+)
+
+type gfcKeyInfo struct {
+	KeyInfo []gfcKey
+}
+
+type gfcKey struct {
+	PrivateKey []byte `json:"privateKey"`
+	Curve      string `json:"curve"`
+}
+
+func formatKeyInfo(ki *types.KeyInfo, format string) (out string, err error) {
+	switch strings.ToLower(format) {
+	case KeyFormatHexLotus, "":
+		data, err := json.Marshal(ki)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(data), nil
+	case KeyFormatJSONLotus:
+		data, err := json.Marshal(ki)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case KeyFormatGfcJSON:
+		gfc := &gfcKeyInfo{
+			KeyInfo: []gfcKey{{
+				PrivateKey: ki.PrivateKey,
+				Curve:      string(ki.Type),
+			}},
+		}
+		data, err := json.Marshal(gfc)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case KeyFormatRaw:
+		return fmt.Sprintf("%s:%s", ki.Type, hex.EncodeToString(ki.PrivateKey)), nil
+	default:
+		return "", fmt.Errorf("key format not support: %s", format)
+	}
+}
+
+// parseKeyInfo detects which of the export formats privateKey is in.
+func parseKeyInfo(privateKey string) (ki *types.KeyInfo, err error) {
+	privateKey = strings.TrimSpace(privateKey)
+	if strings.HasPrefix(privateKey, "{") {
+		return parseKeyInfoJSON([]byte(privateKey))
+	}
+	if i := strings.Index(privateKey, ":"); i > 0 {
+		typ, err := parseKeyType(privateKey[:i])
+		if err != nil {
+			return nil, err
+		}
+		pk, err := hex.DecodeString(strings.TrimPrefix(privateKey[i+1:], "0x"))
+		if err != nil {
+			return nil, err
+		}
+		return &types.KeyInfo{Type: typ, PrivateKey: pk}, nil
+	}
+	data, err := hex.DecodeString(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return nil, fmt.Errorf("raw private key needs a type, use <type>:<hex>")
+	}
+	return parseKeyInfoJSON(data)
+}
+
+func parseKeyInfoJSON(data []byte) (ki *types.KeyInfo, err error) {
+	probe := map[string]json.RawMessage{}
+	err = json.Unmarshal(data, &probe)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := probe["KeyInfo"]; ok {
+		gfc := &gfcKeyInfo{}
+		err = json.Unmarshal(data, gfc)
+		if err != nil {
+			return nil, err
+		}
+		if len(gfc.KeyInfo) != 1 {
+			return nil, fmt.Errorf("expected one key in gfc export, got %d", len(gfc.KeyInfo))
+		}
+		typ, err := parseKeyType(gfc.KeyInfo[0].Curve)
+		if err != nil {
+			return nil, err
+		}
+		return &types.KeyInfo{Type: typ, PrivateKey: gfc.KeyInfo[0].PrivateKey}, nil
+	}
+	ki = &types.KeyInfo{}
+	err = json.Unmarshal(data, ki)
+	if err != nil {
+		return nil, err
+	}
+	ki.Type, err = parseKeyType(string(ki.Type))
+	if err != nil {
+		return nil, err
+	}
+	return ki, nil
+}
@@ -0,0 +1,102 @@
+package epik
+
+import (
+	"time"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/epik/wallet"
+)
+
+//ErrWalletLocked returned by signing methods while the wallet is locked
+var ErrWalletLocked = wallet.ErrWalletLocked
+
+//ErrNotEncrypted the keystore keeps keys in plain text, see Lock
+var ErrNotEncrypted = wallet.ErrNotEncrypted
+
+//Lock wipes decrypted keys from memory, signing fails until Unlock.
+//A NewWallet (in memory) wallet only drops its key cache, the keystore itself
+//holds the keys in plain text and Unlock accepts any passphrase.
+func (w *Wallet) Lock() (err error) {
+	w.lockLk.Lock()
+	defer w.lockLk.Unlock()
+	w.lock()
+	return nil
+}
+
+func (w *Wallet) lock() {
+	if w.lockTimer != nil {
+		w.lockTimer.Stop()
+		w.lockTimer = nil
+	}
+	w.epikWallet.Lock()
+}
+
+//Unlock passphrase is checked against the keystore when it is encrypted
+func (w *Wallet) Unlock(passphrase string) (err error) {
+	w.lockLk.Lock()
+	defer w.lockLk.Unlock()
+	err = w.epikWallet.Unlock(passphrase)
+	if err != nil {
+		return err
+	}
+	w.touch()
+	return nil
+}
+
+//IsLocked ...
+func (w *Wallet) IsLocked() bool {
+	return w.epikWallet.WalletLocked()
+}
+
+//SetAutoLock locks the wallet after seconds of inactivity, 0 disables auto-lock
+func (w *Wallet) SetAutoLock(seconds int64) (err error) {
+	w.lockLk.Lock()
+	defer w.lockLk.Unlock()
+	w.autoLock = time.Duration(seconds) * time.Second
+	if w.autoLock <= 0 && w.lockTimer != nil {
+		w.lockTimer.Stop()
+		w.lockTimer = nil
+	}
+	if !w.epikWallet.WalletLocked() {
+		w.touch()
+	}
+	return nil
+}
+
+// checkUnlocked refuses while locked and otherwise counts as activity.
+func (w *Wallet) checkUnlocked() error {
+	w.lockLk.Lock()
+	defer w.lockLk.Unlock()
+	if w.epikWallet.WalletLocked() {
+		return ErrWalletLocked
+	}
+	// the timer doesn't fire while a phone is suspended, so check the clock too
+	if w.autoLock > 0 && !w.lastActive.IsZero() && time.Since(w.lastActive) >= w.autoLock {
+		w.lock()
+		return ErrWalletLocked
+	}
+	w.touch()
+	return nil
+}
+
+func (w *Wallet) touch() {
+	w.lastActive = time.Now()
+	if w.autoLock <= 0 {
+		return
+	}
+	if w.lockTimer == nil {
+		w.lockTimer = time.AfterFunc(w.autoLock, w.autoLockFired)
+		return
+	}
+	w.lockTimer.Reset(w.autoLock)
+}
+
+func (w *Wallet) autoLockFired() {
+	w.lockLk.Lock()
+	defer w.lockLk.Unlock()
+	// the timer may have fired while touch was resetting it, only lock if
+	// there really was no activity since
+	if w.autoLock <= 0 || w.lockTimer == nil || time.Since(w.lastActive) < w.autoLock {
+		return
+	}
+	w.lock()
+}
@@ -0,0 +1,122 @@
+package epik
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+	"golang.org/x/xerrors"
+)
+
+func newKeystoreWallet(t *testing.T) (w *Wallet, cleanup func()) {
+	dir, err := ioutil.TempDir("", "epik-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = NewWalletWithKeystore(dir, "passphrase")
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return w, func() { os.RemoveAll(dir) }
+}
+
+func TestLock(t *testing.T) {
+	w, cleanup := newKeystoreWallet(t)
+	defer cleanup()
+	addr, err := w.NewKey("secp256k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if !w.IsLocked() {
+		t.Fatal("wallet not locked")
+	}
+
+	if _, err := w.Sign(addr, []byte("epik wallet")); !xerrors.Is(err, ErrWalletLocked) {
+		t.Fatalf("Sign: %v", err)
+	}
+	if _, err := w.SignCID(addr, "bafy2bzacea3wsdh6y3a36tb3skempjoxqpuyompjbmfeyf34fi3uy6uue42v4"); !xerrors.Is(err, ErrWalletLocked) {
+		t.Fatalf("SignCID: %v", err)
+	}
+	if _, err := w.Export(addr); !xerrors.Is(err, ErrWalletLocked) {
+		t.Fatalf("Export: %v", err)
+	}
+	// the lock is checked before the node is used
+	if _, err := w.sendMessage(nil, &types.Message{}); !xerrors.Is(err, ErrWalletLocked) {
+		t.Fatalf("sendMessage: %v", err)
+	}
+
+	if err := w.Unlock("wrong"); err == nil {
+		t.Fatal("wrong passphrase accepted")
+	}
+	if err := w.Unlock("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Sign(addr, []byte("epik wallet")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAutoLock(t *testing.T) {
+	w, cleanup := newKeystoreWallet(t)
+	defer cleanup()
+	if _, err := w.NewKey("bls"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetAutoLock(1); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); !w.IsLocked(); {
+		if time.Now().After(deadline) {
+			t.Fatal("idle wallet not locked")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestAutoLockActivity(t *testing.T) {
+	w, cleanup := newKeystoreWallet(t)
+	defer cleanup()
+	if _, err := w.NewKey("bls"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetAutoLock(60); err != nil {
+		t.Fatal(err)
+	}
+	// a timer that already fired must not lock right after new activity
+	w.autoLockFired()
+	if w.IsLocked() {
+		t.Fatal("wallet locked after recent activity")
+	}
+}
+
+func TestLockMemoryWallet(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.NewKey("secp256k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Sign(addr, []byte("epik wallet")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Sign(addr, []byte("epik wallet")); !xerrors.Is(err, ErrWalletLocked) {
+		t.Fatalf("Sign: %v", err)
+	}
+	if err := w.Unlock(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Sign(addr, []byte("epik wallet")); err != nil {
+		t.Fatal(err)
+	}
+}
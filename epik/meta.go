@@ -0,0 +1,40 @@
+package epik
+
+import (
+	"encoding/json"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/meta"
+)
+
+//AddrInfo address with its metadata, for AddrListWithMeta
+type AddrInfo struct {
+	Address   string         `json:"address"`
+	WatchOnly bool           `json:"watch_only"`
+	Metadata  *meta.Metadata `json:"metadata"`
+}
+
+//SetMetaStore metadata included by AddrListWithMeta
+func (w *Wallet) SetMetaStore(store *meta.Store) {
+	w.metaStore = store
+}
+
+//AddrListWithMeta JSON list of AddrInfo, keys first then watch-only addresses
+func (w *Wallet) AddrListWithMeta() (listJSON string, err error) {
+	addrs, err := w.AddrList()
+	if err != nil {
+		return "", err
+	}
+	list := []*AddrInfo{}
+	for _, addr := range addrs {
+		info := &AddrInfo{
+			Address:   addr,
+			WatchOnly: w.IsWatchOnly(addr),
+		}
+		if w.metaStore != nil {
+			info.Metadata = w.metaStore.Lookup(meta.ChainEpiK, addr)
+		}
+		list = append(list, info)
+	}
+	data, _ := json.Marshal(list)
+	return string(data), nil
+}
@@ -0,0 +1,133 @@
+package epik
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/filecoin-project/go-address"
+)
+
+// networks known to SetNetwork
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+)
+
+//ErrWrongNetwork the address prefix belongs to another network
+var ErrWrongNetwork = fmt.Errorf("address belongs to another network")
+
+var (
+	networks = map[string]address.Network{
+		NetworkMainnet: address.Mainnet,
+		NetworkTestnet: address.Testnet,
+	}
+	// empty until SetNetwork is called, addresses of both networks are accepted until then
+	network   string
+	networkLk sync.RWMutex
+	// address.CurrentNetwork before the first SetNetwork, restored by SetNetwork("")
+	defaultNetwork address.Network
+)
+
+//AddNetwork registers a custom network (devnet, calibration...) using the prefix of mainnet (f) or testnet (t)
+func AddNetwork(name string, prefix string) (err error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return fmt.Errorf("network name is empty")
+	}
+	networkLk.Lock()
+	defer networkLk.Unlock()
+	switch prefix {
+	case address.MainnetPrefix:
+		networks[name] = address.Mainnet
+	case address.TestnetPrefix:
+		networks[name] = address.Testnet
+	default:
+		return fmt.Errorf("address prefix not support: %s", prefix)
+	}
+	return nil
+}
+
+//SetNetwork mainnet, testnet or a network added with AddNetwork. The address prefix is process
+//wide in go-address, so this sets the prefix of address strings and keystore names for every
+//Wallet, and addresses of other networks are rejected afterwards. An empty name accepts both again.
+func SetNetwork(name string) (err error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	networkLk.Lock()
+	defer networkLk.Unlock()
+	if name == "" {
+		if network != "" {
+			address.CurrentNetwork = defaultNetwork
+		}
+		network = ""
+		return nil
+	}
+	n, ok := networks[name]
+	if !ok {
+		return fmt.Errorf("network not support: %s", name)
+	}
+	if network == "" {
+		defaultNetwork = address.CurrentNetwork
+	}
+	address.CurrentNetwork = n
+	network = name
+	return nil
+}
+
+//Network name set by SetNetwork, empty when it was never called
+func Network() string {
+	networkLk.RLock()
+	defer networkLk.RUnlock()
+	return network
+}
+
+//ValidateAddress checks the address syntax, checksum and network prefix
+func (w *Wallet) ValidateAddress(addr string) (err error) {
+	_, err = parseAddress(addr)
+	return err
+}
+
+// parseAddress parses a user entered address, rejecting addresses of
+// another network once SetNetwork was called.
+func parseAddress(addr string) (address.Address, error) {
+	addr = strings.TrimSpace(addr)
+	ad, err := address.NewFromString(addr)
+	if err != nil {
+		return address.Undef, err
+	}
+	networkLk.RLock()
+	defer networkLk.RUnlock()
+	if network == "" {
+		return ad, nil
+	}
+	prefix := address.MainnetPrefix
+	if address.CurrentNetwork == address.Testnet {
+		prefix = address.TestnetPrefix
+	}
+	if !strings.HasPrefix(addr, prefix) {
+		return address.Undef, fmt.Errorf("%s on %s: %w", addr, network, ErrWrongNetwork)
+	}
+	return ad, nil
+}
+
+// checkMessageNetwork runs the To and From strings of a message JSON through
+// parseAddress, unmarshalling into types.Message drops their network prefix.
+func checkMessageNetwork(message []byte) error {
+	var addrs struct {
+		To   string
+		From string
+	}
+	if err := json.Unmarshal(message, &addrs); err != nil {
+		return err
+	}
+	for _, addr := range []string{addrs.To, addrs.From} {
+		if addr == "" {
+			continue
+		}
+		if _, err := parseAddress(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
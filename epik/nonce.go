@@ -0,0 +1,86 @@
+package epik
+
+import (
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+)
+
+// nonceTTL after which the node's nonce is trusted again over a local one
+// that is ahead, in case the messages sent meanwhile left the mpool or a
+// prepared message was never signed. Long enough to sign a prepared message
+// on an offline device.
+const nonceTTL = 10 * time.Minute
+
+// nonceTracker hands out nonces per sender ahead of the node's mpool view,
+// so that messages sent back to back never get the same nonce.
+type nonceTracker struct {
+	lk      sync.Mutex
+	next    map[address.Address]uint64
+	touched map[address.Address]time.Time
+}
+
+// current is the next nonce of addr, the larger of the node's and the local one
+func (t *nonceTracker) current(addr address.Address, nodeNonce uint64) uint64 {
+	next, ok := t.next[addr]
+	if !ok || nodeNonce >= next || time.Since(t.touched[addr]) > nonceTTL {
+		return nodeNonce
+	}
+	return next
+}
+
+func (t *nonceTracker) set(addr address.Address, next uint64) {
+	if t.next == nil {
+		t.next = map[address.Address]uint64{}
+		t.touched = map[address.Address]time.Time{}
+	}
+	t.next[addr] = next
+	t.touched[addr] = time.Now()
+}
+
+// reserve the next nonce of addr
+func (t *nonceTracker) reserve(addr address.Address, nodeNonce uint64) uint64 {
+	t.lk.Lock()
+	defer t.lk.Unlock()
+
+	nonce := t.current(addr, nodeNonce)
+	t.set(addr, nonce+1)
+	return nonce
+}
+
+// release a reserved nonce whose message was not pushed. The last one is
+// handed out again; an earlier one leaves a gap that blocks the later
+// messages, so the local state is dropped and the node, whose nonce stops
+// at the gap, decides the next nonce.
+func (t *nonceTracker) release(addr address.Address, nonce uint64) {
+	t.lk.Lock()
+	defer t.lk.Unlock()
+
+	next, ok := t.next[addr]
+	if !ok {
+		return
+	}
+	if nonce+1 == next {
+		t.set(addr, nonce)
+		return
+	}
+	if nonce < next {
+		t.reset(addr)
+	}
+}
+
+// used records a message pushed with nonce, for messages not filled by the tracker
+func (t *nonceTracker) used(addr address.Address, nonce uint64) {
+	t.lk.Lock()
+	defer t.lk.Unlock()
+
+	if next, ok := t.next[addr]; !ok || nonce >= next {
+		t.set(addr, nonce+1)
+	}
+}
+
+func (t *nonceTracker) reset(addr address.Address) {
+	delete(t.next, addr)
+	delete(t.touched, addr)
+}
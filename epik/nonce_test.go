@@ -0,0 +1,179 @@
+package epik
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/EpiK-Protocol/go-epik/api"
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+	"github.com/filecoin-project/go-address"
+	jsonrpc "github.com/filecoin-project/go-jsonrpc"
+	"github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+)
+
+func TestNonceTracker(t *testing.T) {
+	addr, err := address.NewIDAddress(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &nonceTracker{}
+
+	// the node lags behind messages sent back to back
+	for want := uint64(5); want < 8; want++ {
+		if got := tracker.reserve(addr, 5); got != want {
+			t.Fatalf("reserve: got %d, want %d", got, want)
+		}
+	}
+
+	// the last nonce is handed out again after a failed push
+	tracker.release(addr, 7)
+	if got := tracker.reserve(addr, 5); got != 7 {
+		t.Fatalf("reserve after release: got %d, want 7", got)
+	}
+
+	// a gap hands the nonce back to the node
+	tracker.release(addr, 6)
+	if got := tracker.reserve(addr, 6); got != 6 {
+		t.Fatalf("reserve after gap: got %d, want 6", got)
+	}
+
+	// messages pushed from elsewhere and a node ahead of the tracker
+	tracker.used(addr, 10)
+	if got := tracker.reserve(addr, 6); got != 11 {
+		t.Fatalf("reserve after used: got %d, want 11", got)
+	}
+	if got := tracker.reserve(addr, 20); got != 20 {
+		t.Fatalf("reserve behind node: got %d, want 20", got)
+	}
+
+	// a stale local nonce gives way to the node
+	tracker.touched[addr] = time.Now().Add(-2 * nonceTTL)
+	if got := tracker.reserve(addr, 15); got != 15 {
+		t.Fatalf("reserve stale: got %d, want 15", got)
+	}
+}
+
+// stubNode lags behind: its mpool nonce never moves
+type stubNode struct {
+	api.FullNode
+
+	nonce    uint64
+	nonces   map[address.Address]uint64
+	balances map[address.Address]types.BigInt
+	actors   map[address.Address]*types.Actor
+	pushErr  error
+	pushed   []uint64
+}
+
+func (n *stubNode) MpoolGetNonce(_ context.Context, addr address.Address) (uint64, error) {
+	if nonce, ok := n.nonces[addr]; ok {
+		return nonce, nil
+	}
+	return n.nonce, nil
+}
+
+func (n *stubNode) WalletBalance(_ context.Context, addr address.Address) (types.BigInt, error) {
+	if bal, ok := n.balances[addr]; ok {
+		return bal, nil
+	}
+	return types.NewInt(0), nil
+}
+
+// dialStub makes fullAPI return node until the test ends
+func dialStub(t *testing.T, node api.FullNode) {
+	dial := dialFullNode
+	t.Cleanup(func() { dialFullNode = dial })
+	dialFullNode = func(context.Context, string, http.Header) (api.FullNode, jsonrpc.ClientCloser, error) {
+		return node, func() {}, nil
+	}
+}
+
+// StateGetActor fails like a remote node, with the error text only
+func (n *stubNode) StateGetActor(_ context.Context, addr address.Address, _ types.TipSetKey) (*types.Actor, error) {
+	if actor, ok := n.actors[addr]; ok {
+		return actor, nil
+	}
+	return nil, xerrors.New("load state tree: " + types.ErrActorNotFound.Error())
+}
+
+func (n *stubNode) GasEstimateFeeCap(context.Context, *types.Message, int64, types.TipSetKey) (types.BigInt, error) {
+	return types.NewInt(100), nil
+}
+
+func (n *stubNode) GasEstimateGasLimit(context.Context, *types.Message, types.TipSetKey) (int64, error) {
+	return 1000, nil
+}
+
+func (n *stubNode) MpoolPush(_ context.Context, sm *types.SignedMessage) (cid.Cid, error) {
+	if n.pushErr != nil {
+		return cid.Undef, n.pushErr
+	}
+	n.pushed = append(n.pushed, sm.Message.Nonce)
+	return sm.Cid(), nil
+}
+
+func TestSendMessageNonces(t *testing.T) {
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.NewKey("secp256k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := address.NewFromString(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := &stubNode{nonce: 5}
+	send := func() error {
+		_, err := w.sendMessage(node, &types.Message{From: from, To: from, Value: types.NewInt(1)})
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := send(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	node.pushErr = xerrors.New("mpool full")
+	if err := send(); err == nil {
+		t.Fatal("push error lost")
+	}
+	node.pushErr = nil
+	if err := send(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(node.pushed) != "[5 6 7 8]" {
+		t.Fatalf("pushed nonces %v", node.pushed)
+	}
+
+	// messages prepared for offline signing reserve their nonce too
+	first := &types.Message{From: from, To: from, Value: types.NewInt(1)}
+	second := &types.Message{From: from, To: from, Value: types.NewInt(1)}
+	if err := w.fillMessage(node, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.fillMessage(node, second); err != nil {
+		t.Fatal(err)
+	}
+	if first.Nonce != 9 || second.Nonce != 10 {
+		t.Fatalf("prepared nonces %d and %d", first.Nonce, second.Nonce)
+	}
+	if first.GasLimit != 1250 {
+		t.Fatalf("gas limit %d", first.GasLimit)
+	}
+
+	// the public methods reach the node through fullAPI as well
+	dialStub(t, node)
+	if _, err := w.Send(addr, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(node.pushed) != "[5 6 7 8 11]" {
+		t.Fatalf("pushed nonces %v", node.pushed)
+	}
+}
@@ -0,0 +1,102 @@
+package epik
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+	"github.com/EpiK-Protocol/go-epik/lib/sigs"
+	"github.com/filecoin-project/go-address"
+)
+
+// Offline signing in three steps: PrepareMessage on an online device,
+// SignMessage on the device holding the key (no node needed) and
+// BroadcastSignedMessage back online. Nothing is changed after signing.
+
+//PrepareMessage fills nonce and gas of an unsigned message JSON from the node
+func (w *Wallet) PrepareMessage(message string) (preparedJSON string, err error) {
+	msg := &types.Message{}
+	if err = json.Unmarshal([]byte(message), msg); err != nil {
+		return
+	}
+	if err = checkMessageNetwork([]byte(message)); err != nil {
+		return
+	}
+	if msg.From.Empty() {
+		return "", fmt.Errorf("message has no from address")
+	}
+	node, closer, err := w.fullAPI()
+	if err != nil {
+		return
+	}
+	defer closer()
+	if err = w.fillMessage(node, msg); err != nil {
+		return
+	}
+	data, _ := json.Marshal(msg)
+	return string(data), nil
+}
+
+//SignMessage signs a prepared message JSON offline, returns SignedMessage JSON
+func (w *Wallet) SignMessage(message string) (signedJSON string, err error) {
+	if err = w.checkUnlocked(); err != nil {
+		return
+	}
+	msg := &types.Message{}
+	if err = json.Unmarshal([]byte(message), msg); err != nil {
+		return
+	}
+	if err = checkMessageNetwork([]byte(message)); err != nil {
+		return
+	}
+	if msg.GasLimit == 0 || msg.GasFeeCap.Nil() || msg.GasFeeCap.IsZero() {
+		return "", fmt.Errorf("message is not prepared, gas is not set")
+	}
+	signature, err := w.epikWallet.WalletSign(context.Background(), msg.From, msg.Cid().Bytes())
+	if err != nil {
+		return
+	}
+	data, _ := json.Marshal(&types.SignedMessage{
+		Message:   *msg,
+		Signature: *signature,
+	})
+	return string(data), nil
+}
+
+//BroadcastSignedMessage pushes SignedMessage JSON unchanged
+func (w *Wallet) BroadcastSignedMessage(signedJSON string) (cidStr string, err error) {
+	signed := &types.SignedMessage{}
+	if err = json.Unmarshal([]byte(signedJSON), signed); err != nil {
+		return
+	}
+	var raw struct{ Message json.RawMessage }
+	if err = json.Unmarshal([]byte(signedJSON), &raw); err != nil {
+		return
+	}
+	if err = checkMessageNetwork(raw.Message); err != nil {
+		return
+	}
+	return w.pushSignedMessage(signed)
+}
+
+// pushSignedMessage checks the signature locally when the sender is a key
+// address, so that a message changed after signing fails with a clear error.
+func (w *Wallet) pushSignedMessage(signed *types.SignedMessage) (cidStr string, err error) {
+	if signed.Message.From.Protocol() != address.ID {
+		if err = sigs.Verify(&signed.Signature, signed.Message.From, signed.Message.Cid().Bytes()); err != nil {
+			return "", fmt.Errorf("signature does not match the message: %w", err)
+		}
+	}
+	node, closer, err := w.fullAPI()
+	if err != nil {
+		return
+	}
+	defer closer()
+	c, err := node.MpoolPush(context.Background(), signed)
+	if err != nil {
+		return
+	}
+	w.nonces.used(signed.Message.From, signed.Message.Nonce)
+	return c.String(), nil
+}
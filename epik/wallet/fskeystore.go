@@ -0,0 +1,374 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/xerrors"
+
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+)
+
+const (
+	fsKeyVersion = 1
+
+	// scrypt parameters, kept below the go-ethereum "standard" cost so that
+	// unlocking stays usable on low-end phones.
+	fsScryptN = 1 << 15
+	fsScryptR = 8
+	fsScryptP = 1
+
+	fsKeyLen   = 32
+	fsSaltLen  = 32
+	fsFileMode = 0600
+	fsDirMode  = 0700
+
+	// fsVerifierFile holds fsVerifierText encrypted with the passphrase, so that
+	// the passphrase can be checked while the keystore holds no keys. The name is
+	// not valid base32, it never collides with a key file.
+	fsVerifierFile = ".passphrase"
+)
+
+var fsVerifierText = []byte("epik keystore")
+
+var ErrWrongPassphrase = xerrors.New("wrong passphrase")
+
+// errNothingToCheck is returned by checkPassphrase when there is neither a
+// verifier nor a key to check the passphrase against.
+var errNothingToCheck = xerrors.New("keystore has no passphrase verifier")
+
+// FsKeyStore is a types.KeyStore that keeps every KeyInfo in its own file,
+// encrypted with a key derived from the passphrase (scrypt + AES-GCM).
+type FsKeyStore struct {
+	dir        string
+	passphrase []byte
+
+	lk sync.Mutex
+}
+
+type encryptedKeyInfo struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// NewFsKeyStore opens (or creates) an encrypted keystore in dir. If the
+// directory already holds keys, the passphrase is checked against them.
+func NewFsKeyStore(dir string, passphrase string) (*FsKeyStore, error) {
+	if passphrase == "" {
+		return nil, xerrors.New("passphrase is empty")
+	}
+	if err := os.MkdirAll(dir, fsDirMode); err != nil {
+		return nil, xerrors.Errorf("creating keystore dir: %w", err)
+	}
+	ks := &FsKeyStore{
+		dir:        dir,
+		passphrase: []byte(passphrase),
+	}
+	if err := ks.checkPassphrase(ks.passphrase); err != nil && err != errNothingToCheck {
+		return nil, err
+	}
+	// new keystores, and those written before there was a verifier
+	if _, err := os.Stat(ks.verifierPath()); os.IsNotExist(err) {
+		if err := ks.writeVerifier(); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+// List lists all the keys stored in the KeyStore
+func (ks *FsKeyStore) List() ([]string, error) {
+	ks.lk.Lock()
+	defer ks.lk.Unlock()
+
+	files, err := ioutil.ReadDir(ks.dir)
+	if err != nil {
+		return nil, xerrors.Errorf("reading keystore dir: %w", err)
+	}
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		name, err := decodeKeyName(f.Name())
+		if err != nil {
+			continue // not one of ours
+		}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+// Get gets a key out of keystore and returns KeyInfo corresponding to named key
+func (ks *FsKeyStore) Get(name string) (types.KeyInfo, error) {
+	ks.lk.Lock()
+	defer ks.lk.Unlock()
+
+	if ks.passphrase == nil {
+		return types.KeyInfo{}, ErrWalletLocked
+	}
+
+	data, err := ioutil.ReadFile(ks.keyPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return types.KeyInfo{}, xerrors.Errorf("opening key '%s': %w", name, types.ErrKeyInfoNotFound)
+		}
+		return types.KeyInfo{}, xerrors.Errorf("opening key '%s': %w", name, err)
+	}
+	plain, err := decryptKeyInfo(data, ks.passphrase)
+	if err != nil {
+		return types.KeyInfo{}, xerrors.Errorf("decrypting key '%s': %w", name, err)
+	}
+	defer zeroBytes(plain)
+
+	var ki types.KeyInfo
+	if err := json.Unmarshal(plain, &ki); err != nil {
+		return types.KeyInfo{}, xerrors.Errorf("decoding key '%s': %w", name, err)
+	}
+	return ki, nil
+}
+
+// Put saves a key info under given name
+func (ks *FsKeyStore) Put(name string, ki types.KeyInfo) error {
+	ks.lk.Lock()
+	defer ks.lk.Unlock()
+
+	if ks.passphrase == nil {
+		return ErrWalletLocked
+	}
+
+	plain, err := json.Marshal(ki)
+	if err != nil {
+		return xerrors.Errorf("encoding key '%s': %w", name, err)
+	}
+	defer zeroBytes(plain)
+
+	data, err := encryptKeyInfo(plain, ks.passphrase)
+	if err != nil {
+		return xerrors.Errorf("encrypting key '%s': %w", name, err)
+	}
+
+	// write to a temp file first so a crash never leaves a truncated key behind
+	path := ks.keyPath(name)
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, fsFileMode); err != nil {
+		return xerrors.Errorf("writing key '%s': %w", name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return xerrors.Errorf("writing key '%s': %w", name, err)
+	}
+	return nil
+}
+
+// Delete removes a key from keystore
+func (ks *FsKeyStore) Delete(name string) error {
+	ks.lk.Lock()
+	defer ks.lk.Unlock()
+
+	err := os.Remove(ks.keyPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return xerrors.Errorf("deleting key '%s': %w", name, types.ErrKeyInfoNotFound)
+		}
+		return xerrors.Errorf("deleting key '%s': %w", name, err)
+	}
+	return nil
+}
+
+// Lock forgets the passphrase; Get and Put fail until Unlock.
+func (ks *FsKeyStore) Lock() {
+	ks.lk.Lock()
+	defer ks.lk.Unlock()
+
+	zeroBytes(ks.passphrase)
+	ks.passphrase = nil
+}
+
+// Unlock checks passphrase against the verifier and keeps it for later use.
+func (ks *FsKeyStore) Unlock(passphrase string) error {
+	ks.lk.Lock()
+	defer ks.lk.Unlock()
+
+	p := []byte(passphrase)
+	if err := ks.checkPassphrase(p); err != nil {
+		return err
+	}
+	ks.passphrase = p
+	return nil
+}
+
+func (ks *FsKeyStore) keyPath(name string) string {
+	return filepath.Join(ks.dir, encodeKeyName(name))
+}
+
+func (ks *FsKeyStore) verifierPath() string {
+	return filepath.Join(ks.dir, fsVerifierFile)
+}
+
+func (ks *FsKeyStore) writeVerifier() error {
+	data, err := encryptKeyInfo(fsVerifierText, ks.passphrase)
+	if err != nil {
+		return xerrors.Errorf("encrypting passphrase verifier: %w", err)
+	}
+	if err := ioutil.WriteFile(ks.verifierPath(), data, fsFileMode); err != nil {
+		return xerrors.Errorf("writing passphrase verifier: %w", err)
+	}
+	return nil
+}
+
+// checkPassphrase decrypts the verifier, or any one stored key for keystores
+// written before there was a verifier.
+func (ks *FsKeyStore) checkPassphrase(passphrase []byte) error {
+	data, err := ioutil.ReadFile(ks.verifierPath())
+	if err == nil {
+		plain, err := decryptKeyInfo(data, passphrase)
+		if err != nil {
+			return err
+		}
+		defer zeroBytes(plain)
+		if !bytes.Equal(plain, fsVerifierText) {
+			return ErrWrongPassphrase
+		}
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return xerrors.Errorf("opening passphrase verifier: %w", err)
+	}
+
+	files, err := ioutil.ReadDir(ks.dir)
+	if err != nil {
+		return xerrors.Errorf("reading keystore dir: %w", err)
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		if _, err := decodeKeyName(f.Name()); err != nil {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(ks.dir, f.Name()))
+		if err != nil {
+			return xerrors.Errorf("opening key file: %w", err)
+		}
+		plain, err := decryptKeyInfo(data, passphrase)
+		if err != nil {
+			return err
+		}
+		zeroBytes(plain)
+		return nil
+	}
+	return errNothingToCheck
+}
+
+// key names contain characters like ':' on some platforms, so file names are
+// the base32 encoding of the name
+var keyNameEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func encodeKeyName(name string) string {
+	return keyNameEncoding.EncodeToString([]byte(name))
+}
+
+func decodeKeyName(file string) (string, error) {
+	name, err := keyNameEncoding.DecodeString(file)
+	if err != nil {
+		return "", err
+	}
+	return string(name), nil
+}
+
+func encryptKeyInfo(plain []byte, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, fsSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(passphrase, salt, fsScryptN, fsScryptR, fsScryptP, fsKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(key)
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&encryptedKeyInfo{
+		Version:    fsKeyVersion,
+		KDF:        "scrypt",
+		N:          fsScryptN,
+		R:          fsScryptR,
+		P:          fsScryptP,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plain, nil),
+	})
+}
+
+func decryptKeyInfo(data []byte, passphrase []byte) ([]byte, error) {
+	var eki encryptedKeyInfo
+	if err := json.Unmarshal(data, &eki); err != nil {
+		return nil, err
+	}
+	if eki.Version != fsKeyVersion || eki.KDF != "scrypt" {
+		return nil, xerrors.Errorf("unsupported key file version %d (%s)", eki.Version, eki.KDF)
+	}
+	// the parameters come from the file, don't let a crafted one make scrypt
+	// allocate gigabytes or spin for minutes
+	if eki.N != fsScryptN || eki.R != fsScryptR || eki.P != fsScryptP {
+		return nil, xerrors.Errorf("unsupported scrypt parameters n=%d r=%d p=%d", eki.N, eki.R, eki.P)
+	}
+	key, err := scrypt.Key(passphrase, eki.Salt, eki.N, eki.R, eki.P, fsKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(key)
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(eki.Nonce) != aead.NonceSize() {
+		return nil, xerrors.Errorf("invalid nonce length: %d", len(eki.Nonce))
+	}
+	plain, err := aead.Open(nil, eki.Nonce, eki.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+var _ (types.KeyStore) = (*FsKeyStore)(nil)
+var _ Locker = (*FsKeyStore)(nil)
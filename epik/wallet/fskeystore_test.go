@@ -0,0 +1,99 @@
+package wallet
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"golang.org/x/xerrors"
+
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+)
+
+func TestFsKeyStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "epik-keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ks, err := NewFsKeyStore(dir, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ki := types.KeyInfo{
+		Type:       types.KTSecp256k1,
+		PrivateKey: []byte{1, 2, 3, 4},
+	}
+	if err := ks.Put(KNamePrefix+"f1test", ki); err != nil {
+		t.Fatal(err)
+	}
+
+	// a second instance must see the key, and only with the right passphrase
+	if _, err := NewFsKeyStore(dir, "wrong"); !xerrors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+	ks, err = NewFsKeyStore(dir, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := ks.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0] != KNamePrefix+"f1test" {
+		t.Fatalf("unexpected list: %v", list)
+	}
+	got, err := ks.Get(KNamePrefix + "f1test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != ki.Type || !bytes.Equal(got.PrivateKey, ki.PrivateKey) {
+		t.Fatalf("unexpected key info: %+v", got)
+	}
+
+	if err := ks.Delete(KNamePrefix + "f1test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Get(KNamePrefix + "f1test"); !xerrors.Is(err, types.ErrKeyInfoNotFound) {
+		t.Fatalf("expected ErrKeyInfoNotFound, got %v", err)
+	}
+}
+
+func TestFsKeyStorePassphrase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "epik-keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ks, err := NewFsKeyStore(dir, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// checked against the verifier while there are no keys yet
+	if _, err := NewFsKeyStore(dir, "wrong"); !xerrors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+	ks.Lock()
+	if err := ks.Unlock("wrong"); !xerrors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+	if err := ks.Unlock("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if list, err := ks.List(); err != nil || len(list) != 0 {
+		t.Fatalf("unexpected list: %v %v", list, err)
+	}
+
+	// scrypt parameters are taken from the file only if they are ours
+	data, err := encryptKeyInfo([]byte("{}"), ks.passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.Replace(data, []byte(`"n":32768`), []byte(`"n":1073741824`), 1)
+	if _, err := decryptKeyInfo(data, ks.passphrase); err == nil {
+		t.Fatal("expensive scrypt parameters accepted")
+	}
+}
@@ -26,13 +26,32 @@ const (
 	KDefault     = "default"
 )
 
+var ErrWalletLocked = xerrors.New("wallet locked")
+
+// ErrNotEncrypted is returned when locking a wallet whose keystore is not encrypted
+var ErrNotEncrypted = xerrors.New("keystore is not encrypted")
+var ErrWatchOnly = xerrors.New("watch-only address, no private key")
+
 type LocalWallet struct {
 	keys     map[address.Address]*Key
 	keystore types.KeyStore
 
+	// default address, cached so that it can still be read while locked
+	def    address.Address
+	locked bool
+
+	// watch-only addresses, kept in memory like the hd wallet does
+	watch map[address.Address]struct{}
+
 	lk sync.Mutex
 }
 
+// Locker is implemented by keystores that can drop their secrets on Lock.
+type Locker interface {
+	Lock()
+	Unlock(passphrase string) error
+}
+
 type Default interface {
 	GetDefault(ctx context.Context) (address.Address, error)
 	SetDefault(ctx context.Context, a address.Address) error
@@ -64,6 +83,9 @@ func (w *LocalWallet) WalletSign(ctx context.Context, addr address.Address, msg
 		return nil, err
 	}
 	if ki == nil {
+		if w.walletIsWatch(addr) {
+			return nil, xerrors.Errorf("signing using key '%s': %w", addr.String(), ErrWatchOnly)
+		}
 		return nil, xerrors.Errorf("signing using key '%s': %w", addr.String(), types.ErrKeyInfoNotFound)
 	}
 
@@ -74,6 +96,10 @@ func (w *LocalWallet) findKey(addr address.Address) (*Key, error) {
 	w.lk.Lock()
 	defer w.lk.Unlock()
 
+	if w.locked {
+		return nil, ErrWalletLocked
+	}
+
 	k, ok := w.keys[addr]
 	if ok {
 		return k, nil
@@ -110,26 +136,47 @@ func (w *LocalWallet) tryFind(addr address.Address) (types.KeyInfo, error) {
 	}
 
 	// We got an ErrKeyInfoNotFound error
-	// Try again, this time with the testnet prefix
+	// Try again with the prefixes of the other networks
 
-	tAddress, err := swapMainnetForTestnetPrefix(addr.String()) // TODO: remove testnet
-	if err != nil {
-		return types.KeyInfo{}, err
-	}
+	for _, name := range otherNetworkNames(addr) {
+		ki, err = w.keystore.Get(KNamePrefix + name)
+		if err != nil {
+			if xerrors.Is(err, types.ErrKeyInfoNotFound) {
+				continue
+			}
+			return types.KeyInfo{}, err
+		}
 
-	ki, err = w.keystore.Get(KNamePrefix + tAddress)
-	if err != nil {
-		return types.KeyInfo{}, err
+		// We found it with another prefix
+		// Add this KeyInfo with the current network address string
+		err = w.keystore.Put(KNamePrefix+addr.String(), ki)
+		if err != nil {
+			return types.KeyInfo{}, err
+		}
+
+		return ki, nil
 	}
 
-	// We found it with the testnet prefix
-	// Add this KeyInfo with the mainnet prefix address string
-	err = w.keystore.Put(KNamePrefix+addr.String(), ki)
+	return types.KeyInfo{}, xerrors.Errorf("getting key '%s': %w", addr, types.ErrKeyInfoNotFound)
+}
+
+// findName returns the keystore name of addr under prefix, trying the
+// address strings of all networks; the current network name if none exists.
+func (w *LocalWallet) findName(prefix string, addr address.Address) string {
+	all, err := w.keystore.List()
 	if err != nil {
-		return types.KeyInfo{}, err
+		return prefix + addr.String()
 	}
-
-	return ki, nil
+	names := map[string]bool{}
+	for _, a := range all {
+		names[a] = true
+	}
+	for _, name := range append([]string{addr.String()}, otherNetworkNames(addr)...) {
+		if names[prefix+name] {
+			return prefix + name
+		}
+	}
+	return prefix + addr.String()
 }
 
 func (w *LocalWallet) WalletExport(ctx context.Context, addr address.Address) (*types.KeyInfo, error) {
@@ -138,6 +185,9 @@ func (w *LocalWallet) WalletExport(ctx context.Context, addr address.Address) (*
 		return nil, xerrors.Errorf("failed to find key to export: %w", err)
 	}
 	if k == nil {
+		if w.walletIsWatch(addr) {
+			return nil, ErrWatchOnly
+		}
 		return nil, xerrors.Errorf("key not found")
 	}
 
@@ -148,6 +198,10 @@ func (w *LocalWallet) WalletImport(ctx context.Context, ki *types.KeyInfo) (addr
 	w.lk.Lock()
 	defer w.lk.Unlock()
 
+	if w.locked {
+		return address.Undef, ErrWalletLocked
+	}
+
 	k, err := NewKey(*ki)
 	if err != nil {
 		return address.Undef, xerrors.Errorf("failed to make key: %w", err)
@@ -197,6 +251,13 @@ func (w *LocalWallet) GetDefault(ctx context.Context) (address.Address, error) {
 	w.lk.Lock()
 	defer w.lk.Unlock()
 
+	if w.def != address.Undef {
+		return w.def, nil
+	}
+	if w.locked {
+		return address.Undef, ErrWalletLocked
+	}
+
 	ki, err := w.keystore.Get(KDefault)
 	if err != nil {
 		if xerrors.Is(err, types.ErrKeyInfoNotFound) {
@@ -206,13 +267,14 @@ func (w *LocalWallet) GetDefault(ctx context.Context) (address.Address, error) {
 			}
 			if len(list) > 0 {
 				a := list[0]
-				kii, err := w.keystore.Get(KNamePrefix + a.String())
+				kii, err := w.tryFind(a)
 				if err != nil {
 					return address.Undef, err
 				}
 				if err := w.keystore.Put(KDefault, kii); err != nil {
 					return address.Undef, err
 				}
+				w.def = a
 				return a, nil
 			} else {
 				return address.Undef, xerrors.Errorf("failed to get default key: %w", err)
@@ -226,6 +288,7 @@ func (w *LocalWallet) GetDefault(ctx context.Context) (address.Address, error) {
 	if err != nil {
 		return address.Undef, xerrors.Errorf("failed to read default key from keystore: %w", err)
 	}
+	w.def = k.Address
 
 	return k.Address, nil
 }
@@ -234,7 +297,11 @@ func (w *LocalWallet) SetDefault(ctx context.Context, a address.Address) error {
 	w.lk.Lock()
 	defer w.lk.Unlock()
 
-	ki, err := w.keystore.Get(KNamePrefix + a.String())
+	if w.locked {
+		return ErrWalletLocked
+	}
+
+	ki, err := w.tryFind(a)
 	if err != nil {
 		return err
 	}
@@ -248,6 +315,7 @@ func (w *LocalWallet) SetDefault(ctx context.Context, a address.Address) error {
 	if err := w.keystore.Put(KDefault, ki); err != nil {
 		return err
 	}
+	w.def = a
 
 	return nil
 }
@@ -256,6 +324,10 @@ func (w *LocalWallet) WalletNew(ctx context.Context, typ types.KeyType) (address
 	w.lk.Lock()
 	defer w.lk.Unlock()
 
+	if w.locked {
+		return address.Undef, ErrWalletLocked
+	}
+
 	k, err := GenerateKey(typ)
 	if err != nil {
 		return address.Undef, err
@@ -281,20 +353,37 @@ func (w *LocalWallet) WalletNew(ctx context.Context, typ types.KeyType) (address
 }
 
 func (w *LocalWallet) WalletNewFromSeed(typ types.KeyType, seed []byte) (address.Address, error) {
-	w.lk.Lock()
-	defer w.lk.Unlock()
-
 	k, err := GenerateKeyFromSeed(typ, seed)
 	if err != nil {
 		return address.Undef, err
 	}
+	return w.addKey(k)
+}
+
+// WalletNewFromKeyInfo stores a key derived outside of the wallet, like WalletNewFromSeed
+// it becomes the default key if there is none.
+func (w *LocalWallet) WalletNewFromKeyInfo(ki types.KeyInfo) (address.Address, error) {
+	k, err := NewKey(ki)
+	if err != nil {
+		return address.Undef, err
+	}
+	return w.addKey(k)
+}
+
+func (w *LocalWallet) addKey(k *Key) (address.Address, error) {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	if w.locked {
+		return address.Undef, ErrWalletLocked
+	}
 
 	if err := w.keystore.Put(KNamePrefix+k.Address.String(), k.KeyInfo); err != nil {
 		return address.Undef, xerrors.Errorf("saving to keystore: %w", err)
 	}
 	w.keys[k.Address] = k
 
-	_, err = w.keystore.Get(KDefault)
+	_, err := w.keystore.Get(KDefault)
 	if err != nil {
 		if !xerrors.Is(err, types.ErrKeyInfoNotFound) {
 			return address.Undef, err
@@ -309,6 +398,19 @@ func (w *LocalWallet) WalletNewFromSeed(typ types.KeyType, seed []byte) (address
 }
 
 func (w *LocalWallet) WalletHas(ctx context.Context, addr address.Address) (bool, error) {
+	if w.isLocked() {
+		// keys can't be decrypted, look at the names only
+		list, err := w.WalletList(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, a := range list {
+			if a == addr {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
 	k, err := w.findKey(addr)
 	if err != nil {
 		return false, err
@@ -341,14 +443,11 @@ func (w *LocalWallet) walletDelete(ctx context.Context, addr address.Address) er
 		return xerrors.Errorf("failed to delete key %s: %w", addr, err)
 	}
 
-	tAddr, err := swapMainnetForTestnetPrefix(addr.String())
-	if err != nil {
-		return xerrors.Errorf("failed to swap prefixes: %w", err)
+	for _, name := range otherNetworkNames(addr) {
+		// TODO: Does this always error in the not-found case? Just ignoring an error return for now.
+		_ = w.keystore.Delete(KNamePrefix + name)
 	}
 
-	// TODO: Does this always error in the not-found case? Just ignoring an error return for now.
-	_ = w.keystore.Delete(KNamePrefix + tAddr)
-
 	delete(w.keys, addr)
 
 	return nil
@@ -357,6 +456,7 @@ func (w *LocalWallet) walletDelete(ctx context.Context, addr address.Address) er
 func (w *LocalWallet) deleteDefault() {
 	w.lk.Lock()
 	defer w.lk.Unlock()
+	w.def = address.Undef
 	if err := w.keystore.Delete(KDefault); err != nil {
 		if !xerrors.Is(err, types.ErrKeyInfoNotFound) {
 			log.Warnf("failed to unregister current default key: %s", err)
@@ -377,9 +477,210 @@ func (w *LocalWallet) WalletDelete(ctx context.Context, addr address.Address) er
 	return nil
 }
 
-func swapMainnetForTestnetPrefix(addr string) (string, error) {
+// WalletListTrash lists the addresses of deleted keys that can still be restored.
+func (w *LocalWallet) WalletListTrash(ctx context.Context) ([]address.Address, error) {
+	all, err := w.keystore.List()
+	if err != nil {
+		return nil, xerrors.Errorf("listing keystore: %w", err)
+	}
+
+	out := make([]address.Address, 0, len(all))
+	for _, a := range all {
+		if strings.HasPrefix(a, KTrashPrefix) {
+			addr, err := address.NewFromString(strings.TrimPrefix(a, KTrashPrefix))
+			if err != nil {
+				return nil, xerrors.Errorf("converting name to address: %w", err)
+			}
+			out = append(out, addr)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].String() < out[j].String()
+	})
+
+	return out, nil
+}
+
+// WalletRestore moves a deleted key back out of the trash.
+func (w *LocalWallet) WalletRestore(ctx context.Context, addr address.Address) error {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	if w.locked {
+		return ErrWalletLocked
+	}
+
+	trashName := w.findName(KTrashPrefix, addr)
+	ki, err := w.keystore.Get(trashName)
+	if err != nil {
+		return xerrors.Errorf("failed to find trashed key %s: %w", addr, err)
+	}
+	k, err := NewKey(ki)
+	if err != nil {
+		return xerrors.Errorf("failed to make key: %w", err)
+	}
+	if k.Address != addr {
+		return xerrors.Errorf("trashed key %s belongs to %s", addr, k.Address)
+	}
+
+	if err := w.keystore.Put(KNamePrefix+addr.String(), ki); err != nil {
+		return xerrors.Errorf("saving to keystore: %w", err)
+	}
+	if err := w.keystore.Delete(trashName); err != nil {
+		return xerrors.Errorf("failed to remove key %s from trash: %w", addr, err)
+	}
+
+	return nil
+}
+
+// WalletPurge permanently removes a key from the trash.
+func (w *LocalWallet) WalletPurge(ctx context.Context, addr address.Address) error {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	if w.locked {
+		return ErrWalletLocked
+	}
+
+	if err := w.keystore.Delete(w.findName(KTrashPrefix, addr)); err != nil {
+		return xerrors.Errorf("failed to purge trashed key %s: %w", addr, err)
+	}
+
+	return nil
+}
+
+// WalletAddWatch adds an address (any protocol, including ID addresses) that is
+// monitored without holding its key.
+func (w *LocalWallet) WalletAddWatch(ctx context.Context, addr address.Address) error {
+	has, err := w.WalletHas(ctx, addr)
+	if err != nil {
+		return err
+	}
+	if has {
+		return xerrors.Errorf("key for %s is already in the wallet", addr)
+	}
+
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	if w.locked {
+		return ErrWalletLocked
+	}
+	if w.watch == nil {
+		w.watch = map[address.Address]struct{}{}
+	}
+	w.watch[addr] = struct{}{}
+	return nil
+}
+
+// WalletRemoveWatch stops watching an address.
+func (w *LocalWallet) WalletRemoveWatch(ctx context.Context, addr address.Address) error {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	if w.locked {
+		return ErrWalletLocked
+	}
+	if _, ok := w.watch[addr]; !ok {
+		return xerrors.Errorf("watch address %s not found", addr)
+	}
+	delete(w.watch, addr)
+	return nil
+}
+
+// WalletListWatch lists the watch-only addresses.
+func (w *LocalWallet) WalletListWatch(ctx context.Context) ([]address.Address, error) {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	out := make([]address.Address, 0, len(w.watch))
+	for addr := range w.watch {
+		out = append(out, addr)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].String() < out[j].String()
+	})
+
+	return out, nil
+}
+
+// WalletIsWatch reports whether addr is a watch-only address.
+func (w *LocalWallet) WalletIsWatch(ctx context.Context, addr address.Address) bool {
+	return w.walletIsWatch(addr)
+}
+
+func (w *LocalWallet) walletIsWatch(addr address.Address) bool {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	_, ok := w.watch[addr]
+	return ok
+}
+
+// Lock wipes all decrypted keys from memory and refuses to hand out keys
+// until Unlock is called.
+func (w *LocalWallet) Lock() {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	l, isLocker := w.keystore.(Locker)
+	for a, k := range w.keys {
+		// a plain in-memory keystore shares the key bytes with the cache,
+		// only scrub them when the keystore holds its own encrypted copy
+		if isLocker {
+			for i := range k.PrivateKey {
+				k.PrivateKey[i] = 0
+			}
+		}
+		delete(w.keys, a)
+	}
+	if isLocker {
+		l.Lock()
+	}
+	w.locked = true
+}
+
+// Unlock re-enables key access. The passphrase is only checked when the
+// keystore is a Locker.
+func (w *LocalWallet) Unlock(passphrase string) error {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	if l, ok := w.keystore.(Locker); ok {
+		if err := l.Unlock(passphrase); err != nil {
+			return err
+		}
+	}
+	w.locked = false
+	return nil
+}
+
+// Encrypted reports whether the keystore keeps keys encrypted, Lock only
+// protects keys in that case.
+func (w *LocalWallet) Encrypted() bool {
+	_, ok := w.keystore.(Locker)
+	return ok
+}
+
+// WalletLocked reports whether Lock was called without a matching Unlock.
+func (w *LocalWallet) WalletLocked() bool {
+	return w.isLocked()
+}
+
+func (w *LocalWallet) isLocked() bool {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	return w.locked
+}
+
+// NetworkPrefixes are the address prefixes keys may be stored under
+var NetworkPrefixes = []string{address.MainnetPrefix, address.TestnetPrefix}
+
+func swapNetworkPrefix(addr string, prefix string) (string, error) {
 	aChars := []rune(addr)
-	prefixRunes := []rune(address.TestnetPrefix)
+	prefixRunes := []rune(prefix)
 	if len(prefixRunes) != 1 {
 		return "", xerrors.Errorf("unexpected prefix length: %d", len(prefixRunes))
 	}
@@ -388,6 +689,20 @@ func swapMainnetForTestnetPrefix(addr string) (string, error) {
 	return string(aChars), nil
 }
 
+// otherNetworkNames is addr written with the prefixes of the other networks
+func otherNetworkNames(addr address.Address) []string {
+	current := addr.String()
+	out := []string{}
+	for _, prefix := range NetworkPrefixes {
+		name, err := swapNetworkPrefix(current, prefix)
+		if err != nil || name == current {
+			continue
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
 type nilDefault struct{}
 
 func (n nilDefault) GetDefault(ctx context.Context) (address.Address, error) {
@@ -0,0 +1,53 @@
+package epik
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/epik/wallet"
+)
+
+//ErrWatchOnly returned by signing methods for watch-only addresses
+var ErrWatchOnly = wallet.ErrWatchOnly
+
+//AddWatchAddress monitor an f1/f3/ID address without its key, queries work and signing fails with ErrWatchOnly.
+//Like hd watch accounts the list is kept in memory only, and can't be changed while locked.
+func (w *Wallet) AddWatchAddress(addr string) (err error) {
+	ad, err := parseAddress(addr)
+	if err != nil {
+		return
+	}
+	return w.epikWallet.WalletAddWatch(context.Background(), ad)
+}
+
+//RemoveWatchAddress ...
+func (w *Wallet) RemoveWatchAddress(addr string) (err error) {
+	ad, err := parseAddress(addr)
+	if err != nil {
+		return
+	}
+	return w.epikWallet.WalletRemoveWatch(context.Background(), ad)
+}
+
+//WatchList JSON list of watch-only addresses
+func (w *Wallet) WatchList() (listJSON string, err error) {
+	addrs, err := w.epikWallet.WalletListWatch(context.Background())
+	if err != nil {
+		return "", err
+	}
+	list := []string{}
+	for _, addr := range addrs {
+		list = append(list, addr.String())
+	}
+	data, _ := json.Marshal(list)
+	return string(data), nil
+}
+
+//IsWatchOnly ...
+func (w *Wallet) IsWatchOnly(addr string) bool {
+	ad, err := parseAddress(addr)
+	if err != nil {
+		return false
+	}
+	return w.epikWallet.WalletIsWatch(context.Background(), ad)
+}
@@ -0,0 +1,64 @@
+package epik
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/filecoin-project/go-address"
+)
+
+// Only KeySchemeBIP32 secp256k1 keys are BIP32 keys themselves, addresses of
+// the legacy and eip2333 schemes can't be derived from an extended public key.
+
+//ExportXpub extended public key of an account level path, e.g. m/44'/461'/0'.
+//It covers the secp256k1 addresses of the bip32 scheme below that path.
+func (w *Wallet) ExportXpub(seed []byte, path string) (xpub string, err error) {
+	key, err := epikHDKey(seed, path)
+	if err != nil {
+		return "", err
+	}
+	pub, err := key.Neuter()
+	if err != nil {
+		return "", err
+	}
+	return pub.String(), nil
+}
+
+//XpubAddress secp256k1 address at path relative to xpub, m/0/0 is the first receive address
+func XpubAddress(xpub string, path string) (addr string, err error) {
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return "", err
+	}
+	if key.IsPrivate() {
+		return "", fmt.Errorf("not an extended public key")
+	}
+	key, err = deriveHDKey(key, path)
+	if err != nil {
+		return "", err
+	}
+	pub, err := key.ECPubKey()
+	if err != nil {
+		return "", err
+	}
+	ad, err := address.NewSecp256k1Address(pub.SerializeUncompressed())
+	if err != nil {
+		return "", err
+	}
+	return ad.String(), nil
+}
+
+//AddWatchFromXpub derives the address at path relative to xpub and adds it as a watch-only address
+func (w *Wallet) AddWatchFromXpub(xpub string, path string) (addr string, err error) {
+	addr, err = XpubAddress(xpub, path)
+	if err != nil {
+		return "", err
+	}
+	if w.IsWatchOnly(addr) {
+		return addr, nil
+	}
+	if err = w.AddWatchAddress(addr); err != nil {
+		return "", err
+	}
+	return addr, nil
+}
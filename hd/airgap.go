@@ -0,0 +1,143 @@
+package hd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/airgap"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/shopspring/decimal"
+)
+
+// Air-gapped transfers: CreateTransaction online, SignTransaction on the
+// device holding the key, SendSignedTransaction back online. Transactions
+// travel as airgap envelopes, see airgap.Split for multi-part QR codes.
+// Only plain ETH transfers are prepared, token transfers and uniswap calls
+// still need the key online.
+
+// unsignedTx is the EIP-155 signing payload, it carries the chain id so the
+// offline device needs no node.
+type unsignedTx struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	Gas      uint64
+	To       *common.Address `rlp:"nil"`
+	Value    *big.Int
+	Data     []byte
+	ChainID  *big.Int
+	R, S     uint
+}
+
+//CreateTransaction unsigned ETH transfer envelope with nonce and gas filled from the node
+func (wallet *Wallet) CreateTransaction(from string, to string, amount string) (envelope string, err error) {
+	if !checkAddress(from) || !checkAddress(to) {
+		return "", fmt.Errorf("address error")
+	}
+	client, err := ethclient.DialContext(context.Background(), wallet.rpcURL)
+	if err != nil {
+		return
+	}
+	defer client.Close()
+	toAddr := common.HexToAddress(to)
+	amountWei, err := decimal.NewFromString(amount)
+	if err != nil {
+		return "", err
+	}
+	amountWei = amountWei.Mul(decimal.NewFromBigInt(big.NewInt(1), 18))
+	nonce, err := client.PendingNonceAt(context.Background(), common.HexToAddress(from))
+	if err != nil {
+		return "", err
+	}
+	gasPrice, err := client.SuggestGasPrice(context.Background())
+	if err != nil {
+		return "", err
+	}
+	gasPrice = new(big.Int).Add(gasPrice, new(big.Int).Div(gasPrice, big.NewInt(10)))
+	chainID, err := client.NetworkID(context.Background())
+	if err != nil {
+		return "", err
+	}
+	data, err := rlp.EncodeToBytes(&unsignedTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      21000,
+		To:       &toAddr,
+		Value:    amountWei.BigInt(),
+		ChainID:  chainID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return airgap.Encode(airgap.KindETHTransaction, data)
+}
+
+//SignTransaction signs an unsigned transaction envelope offline, returns a signed transaction envelope
+func (wallet *Wallet) SignTransaction(from string, envelope string) (signedEnvelope string, err error) {
+	if !checkAddress(from) {
+		return "", fmt.Errorf("address error")
+	}
+	payload, err := airgap.DecodeKind(envelope, airgap.KindETHTransaction)
+	if err != nil {
+		return
+	}
+	utx := &unsignedTx{}
+	if err = rlp.DecodeBytes(payload, utx); err != nil {
+		return "", fmt.Errorf("invalid transaction: %w", err)
+	}
+	if utx.ChainID == nil || utx.ChainID.Sign() <= 0 {
+		return "", fmt.Errorf("transaction has no chain id")
+	}
+	privateKey, err := wallet.getPrivateKey(common.HexToAddress(from))
+	if err != nil {
+		return "", err
+	}
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    utx.Nonce,
+		GasPrice: utx.GasPrice,
+		Gas:      utx.Gas,
+		To:       utx.To,
+		Value:    utx.Value,
+		Data:     utx.Data,
+	})
+	signer := types.LatestSignerForChainID(utx.ChainID)
+	signature, err := crypto.Sign(signer.Hash(tx).Bytes(), privateKey)
+	if err != nil {
+		return "", err
+	}
+	signedTx, err := tx.WithSignature(signer, signature)
+	if err != nil {
+		return "", err
+	}
+	data, err := signedTx.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return airgap.Encode(airgap.KindETHSignedTransaction, data)
+}
+
+//SendSignedTransaction broadcasts a signed transaction envelope
+func (wallet *Wallet) SendSignedTransaction(signedEnvelope string) (txHash string, err error) {
+	payload, err := airgap.DecodeKind(signedEnvelope, airgap.KindETHSignedTransaction)
+	if err != nil {
+		return
+	}
+	signedTx := &types.Transaction{}
+	if err = signedTx.UnmarshalBinary(payload); err != nil {
+		return "", fmt.Errorf("invalid transaction: %w", err)
+	}
+	client, err := ethclient.DialContext(context.Background(), wallet.rpcURL)
+	if err != nil {
+		return
+	}
+	defer client.Close()
+	if err = client.SendTransaction(context.Background(), signedTx); err != nil {
+		return "", err
+	}
+	return signedTx.Hash().String(), nil
+}
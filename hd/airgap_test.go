@@ -0,0 +1,76 @@
+package hd
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/airgap"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestSignTransaction(t *testing.T) {
+	w, err := NewFromMnemonic(testMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := w.Derive("m/44'/60'/0'/0/0", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x008AeEda4D805471dF9b2A5B0f38A0C3bCBA786b")
+	encode := func(utx *unsignedTx) string {
+		data, err := rlp.EncodeToBytes(utx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		envelope, err := airgap.Encode(airgap.KindETHTransaction, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return envelope
+	}
+
+	envelope := encode(&unsignedTx{
+		Nonce:    9,
+		GasPrice: big.NewInt(20000000000),
+		Gas:      21000,
+		To:       &to,
+		Value:    big.NewInt(1000000000000000000),
+		ChainID:  big.NewInt(3),
+	})
+	signedEnvelope, err := w.SignTransaction(from, envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := airgap.DecodeKind(signedEnvelope, airgap.KindETHSignedTransaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &types.Transaction{}
+	if err := tx.UnmarshalBinary(payload); err != nil {
+		t.Fatal(err)
+	}
+	if tx.ChainId().Int64() != 3 || tx.Nonce() != 9 || *tx.To() != to {
+		t.Fatalf("transaction changed while signing: chain %s nonce %d to %s", tx.ChainId(), tx.Nonce(), tx.To().Hex())
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(3)), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender.Hex() != from {
+		t.Fatalf("signed by %s, want %s", sender.Hex(), from)
+	}
+
+	if _, err := w.SignTransaction(from, signedEnvelope); err == nil {
+		t.Fatal("signed envelope signed again")
+	}
+	noChain := encode(&unsignedTx{Nonce: 9, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)})
+	if _, err := w.SignTransaction(from, noChain); err == nil {
+		t.Fatal("transaction without chain id signed")
+	}
+	if _, err := w.SignTransaction(to.Hex(), envelope); err == nil {
+		t.Fatal("signed for an account the wallet does not hold")
+	}
+}
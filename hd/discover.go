@@ -0,0 +1,71 @@
+package hd
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/recovery"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultPathTemplate BIP44 path of RecoverAccounts
+const DefaultPathTemplate = "m/44'/60'/0'/0/{index}"
+
+// accountState is the part of ethclient.Client read by RecoverAccounts
+type accountState interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+//RecoverAccounts walks the indexes of pathTemplate until gapLimit consecutive addresses have no balance and no
+//transactions. Used accounts are pinned to the wallet and returned with their paths.
+func (wallet *Wallet) RecoverAccounts(pathTemplate string, gapLimit int) (accountsJSON string, err error) {
+	client, err := ethclient.DialContext(context.Background(), wallet.rpcURL)
+	if err != nil {
+		return
+	}
+	defer client.Close()
+	recovered, err := wallet.recoverAccounts(client, pathTemplate, gapLimit)
+	if err != nil {
+		return "", err
+	}
+	data, _ := json.Marshal(recovered)
+	return string(data), nil
+}
+
+func (wallet *Wallet) recoverAccounts(state accountState, pathTemplate string, gapLimit int) ([]*recovery.RecoveredAccount, error) {
+	if pathTemplate == "" {
+		pathTemplate = DefaultPathTemplate
+	}
+	ctx := context.Background()
+	return recovery.Walk(pathTemplate, gapLimit, func(path string) (*recovery.RecoveredAccount, bool, error) {
+		address, err := wallet.Derive(path, false)
+		if err != nil {
+			return nil, false, err
+		}
+		addr := common.HexToAddress(address)
+		bal, err := state.BalanceAt(ctx, addr, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		nonce, err := state.PendingNonceAt(ctx, addr)
+		if err != nil {
+			return nil, false, err
+		}
+		if bal.Sign() == 0 && nonce == 0 {
+			return nil, false, nil
+		}
+		if _, err := wallet.Derive(path, true); err != nil {
+			return nil, false, err
+		}
+		return &recovery.RecoveredAccount{
+			Path:    path,
+			Address: address,
+			Balance: decimal.NewFromBigInt(bal, -18).String(),
+			Nonce:   nonce,
+		}, true, nil
+	})
+}
@@ -0,0 +1,59 @@
+package hd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// stubState answers RecoverAccounts from memory
+type stubState struct {
+	balances map[common.Address]*big.Int
+	nonces   map[common.Address]uint64
+}
+
+func (s *stubState) BalanceAt(_ context.Context, account common.Address, _ *big.Int) (*big.Int, error) {
+	if bal, ok := s.balances[account]; ok {
+		return bal, nil
+	}
+	return big.NewInt(0), nil
+}
+
+func (s *stubState) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	return s.nonces[account], nil
+}
+
+// the gap limit walk is tested in recovery, this covers the node lookups and the pinning
+func TestRecoverAccounts(t *testing.T) {
+	w, err := NewFromMnemonic(testMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs := make([]string, 2)
+	for i := range addrs {
+		if addrs[i], err = w.Derive(fmt.Sprintf("m/44'/60'/0'/0/%d", i), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// index 0 holds funds, index 1 only sent transactions, index 2 is unused
+	state := &stubState{
+		balances: map[common.Address]*big.Int{common.HexToAddress(addrs[0]): big.NewInt(2500000000000000000)},
+		nonces:   map[common.Address]uint64{common.HexToAddress(addrs[1]): 3},
+	}
+	recovered, err := w.recoverAccounts(state, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(recovered)
+	want := fmt.Sprintf(`[{"path":"m/44'/60'/0'/0/0","address":"%s","balance":"2.5","nonce":0},{"path":"m/44'/60'/0'/0/1","address":"%s","balance":"0","nonce":3}]`, addrs[0], addrs[1])
+	if string(data) != want {
+		t.Fatalf("recovered %s, want %s", data, want)
+	}
+	if accounts := w.Accounts(); accounts != `["`+addrs[0]+`","`+addrs[1]+`"]` {
+		t.Fatalf("pinned %s", accounts)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"crypto/ecdsa"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -17,8 +16,9 @@ import (
 	"github.com/EpiK-Protocol/epik-wallet-golib/abi/uniswap"
 	"github.com/EpiK-Protocol/epik-wallet-golib/abi/univ2"
 	"github.com/EpiK-Protocol/epik-wallet-golib/abi/usdt"
-	"github.com/tyler-smith/go-bip39"
+	"github.com/EpiK-Protocol/epik-wallet-golib/meta"
 
+	"github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -34,6 +34,24 @@ import (
 type Wallet struct {
 	hdWallet *hdwallet.Wallet
 	rpcURL   string
+
+	// masterKey is kept for xpub export, xpub is set instead of both keys for NewFromXpub wallets
+	masterKey *hdkeychain.ExtendedKey
+	xpub      *hdkeychain.ExtendedKey
+
+	// non-HD accounts added with ImportKeystore/ImportPrivateKey
+	imported      map[common.Address]*ecdsa.PrivateKey
+	importedAddrs []common.Address
+
+	// watch-only accounts added with AddWatchAccount
+	watch      map[common.Address]bool
+	watchAddrs []common.Address
+
+	metaStore *meta.Store
+
+	// set by Lock, the HD accounts are kept so they can still be listed
+	locked         bool
+	lockedAccounts []accounts.Account
 }
 
 type currencyType string
@@ -91,12 +109,7 @@ func SetDebug(debug bool) {
 
 //NewFromMnemonic ...
 func NewFromMnemonic(mnemonic string) (wallet *Wallet, err error) {
-	wallet = &Wallet{}
-	wallet.hdWallet, err = hdwallet.NewFromMnemonic(mnemonic)
-	if err != nil {
-		return nil, err
-	}
-	return
+	return NewFromMnemonicWithPassphrase(mnemonic, "")
 }
 
 //NewFromSeed ...
@@ -106,6 +119,10 @@ func NewFromSeed(seed []byte) (wallet *Wallet, err error) {
 	if err != nil {
 		return nil, err
 	}
+	wallet.masterKey, err = newMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
 	return
 }
 
@@ -114,12 +131,23 @@ func NewMnemonic(bits int) (mnemonic string, err error) {
 	return hdwallet.NewMnemonic(bits)
 }
 
-//SeedFromMnemonic ...
-func SeedFromMnemonic(mnemonic string) (seed []byte, err error) {
-	if !bip39.IsMnemonicValid(mnemonic) {
-		return nil, errors.New("mnemonic is invalid")
+//NewFromMnemonicWithPassphrase mnemonic protected by a BIP39 passphrase (25th word)
+func NewFromMnemonicWithPassphrase(mnemonic string, passphrase string) (wallet *Wallet, err error) {
+	seed, err := SeedFromMnemonicWithPassphrase(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
 	}
-	return hdwallet.NewSeedFromMnemonic(mnemonic)
+	return NewFromSeed(seed)
+}
+
+//SeedFromMnemonic mnemonic in any supported language
+func SeedFromMnemonic(mnemonic string) (seed []byte, err error) {
+	return mnemonicSeed(mnemonic, "")
+}
+
+//SeedFromMnemonicWithPassphrase seed for both chains, pass it to epik Wallet.GenerateKey
+func SeedFromMnemonicWithPassphrase(mnemonic string, passphrase string) (seed []byte, err error) {
+	return mnemonicSeed(mnemonic, passphrase)
 }
 
 //NewSeed ...
@@ -133,13 +161,18 @@ func (wallet *Wallet) SetRPC(url string) (err error) {
 	return
 }
 
-//Accounts ...
+//Accounts HD and imported accounts, followed by watch-only accounts
 func (wallet *Wallet) Accounts() (addrs string) {
-	accs := wallet.hdWallet.Accounts()
 	addresses := []string{}
-	for _, acc := range accs {
+	for _, acc := range wallet.hdAccounts() {
 		addresses = append(addresses, acc.Address.Hex())
 	}
+	for _, addr := range wallet.importedAddrs {
+		addresses = append(addresses, addr.Hex())
+	}
+	for _, addr := range wallet.watchAddrs {
+		addresses = append(addresses, addr.Hex())
+	}
 	data, _ := json.Marshal(&addresses)
 	return string(data)
 }
@@ -158,12 +191,20 @@ func (wallet *Wallet) Export(address string) (privateKey string, err error) {
 //Contains ...
 func (wallet *Wallet) Contains(address string) bool {
 	addr := common.HexToAddress(address)
-	account := accounts.Account{Address: addr}
-	return wallet.hdWallet.Contains(account)
+	if _, ok := wallet.imported[addr]; ok {
+		return true
+	}
+	return wallet.hasHDAccount(addr)
 }
 
 //Derive ...
 func (wallet *Wallet) Derive(path string, pin bool) (address string, err error) {
+	if wallet.xpub != nil {
+		return wallet.deriveXpub(path, pin)
+	}
+	if wallet.locked {
+		return "", ErrWalletLocked
+	}
 	p, err := hdwallet.ParseDerivationPath(path)
 	if err != nil {
 		return
@@ -177,16 +218,20 @@ func (wallet *Wallet) Derive(path string, pin bool) (address string, err error)
 
 //SignHash ...
 func (wallet *Wallet) SignHash(address string, hash []byte) (signature []byte, err error) {
-	addr := common.HexToAddress(address)
-	account := accounts.Account{Address: addr}
-	return wallet.hdWallet.SignHash(account, hash)
+	privateKey, err := wallet.getPrivateKey(common.HexToAddress(address))
+	if err != nil {
+		return
+	}
+	return crypto.Sign(hash, privateKey)
 }
 
 //SignText ...
 func (wallet *Wallet) SignText(address string, text string) (signature []byte, err error) {
-	addr := common.HexToAddress(address)
-	account := accounts.Account{Address: addr}
-	return wallet.hdWallet.SignText(account, []byte(text))
+	privateKey, err := wallet.getPrivateKey(common.HexToAddress(address))
+	if err != nil {
+		return
+	}
+	return crypto.Sign(accounts.TextHash([]byte(text)), privateKey)
 }
 
 //Balance ...
@@ -304,6 +349,9 @@ func (wallet *Wallet) Transfer(from string, to string, amount string) (txHash st
 	if !checkAddress(from) || !checkAddress(to) {
 		return "", fmt.Errorf("address error")
 	}
+	if err = wallet.checkCanSign(common.HexToAddress(from)); err != nil {
+		return
+	}
 	client, err := ethclient.DialContext(context.Background(), wallet.rpcURL)
 	if err != nil {
 		return
@@ -352,6 +400,9 @@ func (wallet *Wallet) TransferToken(from string, to string, currency string, amo
 	if !checkAddress(from) || !checkAddress(to) {
 		return "", fmt.Errorf("address error")
 	}
+	if err = wallet.checkCanSign(common.HexToAddress(from)); err != nil {
+		return
+	}
 	client, err := ethclient.DialContext(context.Background(), wallet.rpcURL)
 	if err != nil {
 		return
@@ -767,6 +818,9 @@ func (wallet *Wallet) approve(address common.Address, currency string) (allowed
 func (wallet *Wallet) UniswapAddLiquidity(address, tokenA, tokenB, amountADesired, amountBDesired, amountAMin, amountBMin string, deadline string) (txHash string, err error) {
 	//converting
 	addr := common.HexToAddress(address)
+	if err = wallet.checkCanSign(addr); err != nil {
+		return
+	}
 	contact := common.HexToAddress(uniswapContract)
 	amAdesiredBig, err := decimal.NewFromString(amountADesired)
 	if err != nil {
@@ -848,6 +902,9 @@ func (wallet *Wallet) UniswapAddLiquidity(address, tokenA, tokenB, amountADesire
 func (wallet *Wallet) UniswapRemoveLiquidity(address, tokenA, tokenB, liquidity, amountAMin, amountBMin, deadline string) (txHash string, err error) {
 	//converting
 	addr := common.HexToAddress(address)
+	if err = wallet.checkCanSign(addr); err != nil {
+		return
+	}
 	contract := common.HexToAddress(uniswapContract)
 	liquidityBig, err := decimal.NewFromString(liquidity)
 	if err != nil {
@@ -921,6 +978,9 @@ func (wallet *Wallet) UniswapExactTokenForTokens(address, tokenA, tokenB, amount
 
 	//converting
 	addr := common.HexToAddress(address)
+	if err = wallet.checkCanSign(addr); err != nil {
+		return
+	}
 	contract := common.HexToAddress(uniswapContract)
 	path := []common.Address{}
 	amInBig, err := decimal.NewFromString(amountIn)
@@ -1093,6 +1153,15 @@ func (wallet *Wallet) UniswapInfo(address string) (info *UniswapInfo, err error)
 }
 
 func (wallet *Wallet) getPrivateKey(address common.Address) (priKey *ecdsa.PrivateKey, err error) {
+	if wallet.locked {
+		return nil, ErrWalletLocked
+	}
+	if pk, ok := wallet.imported[address]; ok {
+		return pk, nil
+	}
+	if err = wallet.checkCanSign(address); err != nil {
+		return nil, err
+	}
 	var account accounts.Account
 	find := false
 	for _, acc := range wallet.hdWallet.Accounts() {
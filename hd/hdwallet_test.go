@@ -1,3 +1,9 @@
+//go:build ropsten
+// +build ropsten
+
+// These tests send real transactions from a funded ropsten wallet that init
+// connects to, run them with -tags ropsten.
+
 package hd
 
 import (
@@ -0,0 +1,86 @@
+package hd
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashicorp/go-uuid"
+)
+
+// encryptedKeyJSONV3 is the Web3 Secret Storage layout written by geth,
+// MetaMask and imToken.
+type encryptedKeyJSONV3 struct {
+	Address string              `json:"address"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+	ID      string              `json:"id"`
+	Version int                 `json:"version"`
+}
+
+//ImportKeystore adds the key of a V3 keystore JSON as a non-HD account
+func (wallet *Wallet) ImportKeystore(keystoreJSON string, passphrase string) (address string, err error) {
+	key, err := keystore.DecryptKey([]byte(keystoreJSON), passphrase)
+	if err != nil {
+		return
+	}
+	return wallet.importKey(key.PrivateKey), nil
+}
+
+//ImportPrivateKey adds a hex private key as a non-HD account
+func (wallet *Wallet) ImportPrivateKey(privateKey string) (address string, err error) {
+	pk, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(privateKey), "0x"))
+	if err != nil {
+		return
+	}
+	return wallet.importKey(pk), nil
+}
+
+//ExportKeystore account as V3 keystore JSON encrypted with passphrase
+func (wallet *Wallet) ExportKeystore(address string, passphrase string) (keystoreJSON string, err error) {
+	addr := common.HexToAddress(address)
+	pk, err := wallet.getPrivateKey(addr)
+	if err != nil {
+		return
+	}
+	key := crypto.FromECDSA(pk)
+	defer func() {
+		for i := range key {
+			key[i] = 0
+		}
+	}()
+	// light scrypt params, the standard ones need 256MB which older phones don't have
+	cryptoStruct, err := keystore.EncryptDataV3(key, []byte(passphrase), keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		return
+	}
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(&encryptedKeyJSONV3{
+		Address: hex.EncodeToString(addr[:]),
+		Crypto:  cryptoStruct,
+		ID:      id,
+		Version: 3,
+	})
+	if err != nil {
+		return
+	}
+	return string(data), nil
+}
+
+func (wallet *Wallet) importKey(pk *ecdsa.PrivateKey) (address string) {
+	addr := crypto.PubkeyToAddress(pk.PublicKey)
+	if wallet.imported == nil {
+		wallet.imported = map[common.Address]*ecdsa.PrivateKey{}
+	}
+	if _, ok := wallet.imported[addr]; !ok {
+		wallet.importedAddrs = append(wallet.importedAddrs, addr)
+	}
+	wallet.imported[addr] = pk
+	return addr.Hex()
+}
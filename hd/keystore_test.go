@@ -0,0 +1,88 @@
+package hd
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// test vectors of the Web3 Secret Storage Definition, password "testpassword"
+var keystoreVectors = map[string]string{
+	"pbkdf2": `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+	"scrypt": `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+}
+
+const (
+	keystoreVectorKey     = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	keystoreVectorAddress = "0x008aeeda4d805471df9b2a5b0f38a0c3bcba786b"
+)
+
+func TestImportKeystore(t *testing.T) {
+	for kdf, keystoreJSON := range keystoreVectors {
+		w, err := NewFromMnemonic(testMnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.ImportKeystore(keystoreJSON, "wrongpassword"); err == nil {
+			t.Fatalf("%s: wrong password accepted", kdf)
+		}
+		address, err := w.ImportKeystore(keystoreJSON, "testpassword")
+		if err != nil {
+			t.Fatalf("%s: %s", kdf, err)
+		}
+		if !strings.EqualFold(address, keystoreVectorAddress) {
+			t.Fatalf("%s: address %s, want %s", kdf, address, keystoreVectorAddress)
+		}
+		pk, err := w.getPrivateKey(common.HexToAddress(address))
+		if err != nil {
+			t.Fatalf("%s: %s", kdf, err)
+		}
+		if hex.EncodeToString(crypto.FromECDSA(pk)) != keystoreVectorKey {
+			t.Fatalf("%s: imported key %x", kdf, crypto.FromECDSA(pk))
+		}
+		if !w.Contains(address) || !strings.Contains(w.Accounts(), address) {
+			t.Fatalf("%s: imported account not listed: %s", kdf, w.Accounts())
+		}
+	}
+}
+
+func TestExportKeystore(t *testing.T) {
+	w, err := NewFromMnemonic(testMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := w.ImportPrivateKey("0x" + keystoreVectorKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	derived, err := w.Derive("m/44'/60'/0'/0/0", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, address := range []string{imported, derived} {
+		keystoreJSON, err := w.ExportKeystore(address, "round trip")
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, err := NewFromMnemonic("legal winner thank year wave sausage worth useful legal winner thank yellow")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := other.ImportKeystore(keystoreJSON, "round trip")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != address {
+			t.Fatalf("round trip address %s, want %s", got, address)
+		}
+		want, _ := w.Export(address)
+		if key, _ := other.Export(got); key != want {
+			t.Fatalf("%s: round trip changed the key", address)
+		}
+	}
+}
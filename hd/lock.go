@@ -0,0 +1,86 @@
+package hd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
+)
+
+//ErrWalletLocked returned by signing methods and Derive while the wallet is locked
+var ErrWalletLocked = errors.New("wallet locked")
+
+//Lock drops the seed and the HD keys, accounts stay listed and queries keep working.
+//Signing and Derive fail with ErrWalletLocked until UnlockSeed.
+func (wallet *Wallet) Lock() {
+	if wallet.locked {
+		return
+	}
+	if wallet.hdWallet != nil {
+		wallet.lockedAccounts = wallet.hdWallet.Accounts()
+	}
+	wallet.hdWallet = nil
+	wallet.masterKey = nil
+	wallet.locked = true
+}
+
+//UnlockSeed restores the HD keys from the seed the wallet was made with,
+//the accounts pinned before Lock are derived again
+func (wallet *Wallet) UnlockSeed(seed []byte) (err error) {
+	if !wallet.locked {
+		return nil
+	}
+	hdWallet, err := hdwallet.NewFromSeed(seed)
+	if err != nil {
+		return err
+	}
+	masterKey, err := newMasterKey(seed)
+	if err != nil {
+		return err
+	}
+	for _, acc := range wallet.lockedAccounts {
+		p, err := hdwallet.ParseDerivationPath(acc.URL.Path)
+		if err != nil {
+			return err
+		}
+		derived, err := hdWallet.Derive(p, true)
+		if err != nil {
+			return err
+		}
+		if derived.Address != acc.Address {
+			return fmt.Errorf("seed does not match account %s", acc.Address.Hex())
+		}
+	}
+	wallet.hdWallet = hdWallet
+	wallet.masterKey = masterKey
+	wallet.lockedAccounts = nil
+	wallet.locked = false
+	return nil
+}
+
+//IsLocked ...
+func (wallet *Wallet) IsLocked() bool {
+	return wallet.locked
+}
+
+// hdAccounts are the pinned HD accounts, also while locked
+func (wallet *Wallet) hdAccounts() []accounts.Account {
+	if wallet.locked {
+		return wallet.lockedAccounts
+	}
+	if wallet.hdWallet == nil {
+		return nil
+	}
+	return wallet.hdWallet.Accounts()
+}
+
+func (wallet *Wallet) hasHDAccount(address common.Address) bool {
+	for _, acc := range wallet.hdAccounts() {
+		if acc.Address == address {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package hd
+
+import (
+	"encoding/json"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/meta"
+)
+
+//AccountInfo account with its metadata, for AccountsWithMeta
+type AccountInfo struct {
+	Address   string         `json:"address"`
+	WatchOnly bool           `json:"watch_only"`
+	Metadata  *meta.Metadata `json:"metadata"`
+}
+
+//SetMetaStore metadata included by AccountsWithMeta
+func (wallet *Wallet) SetMetaStore(store *meta.Store) {
+	wallet.metaStore = store
+}
+
+//AccountsWithMeta JSON list of AccountInfo in the order of Accounts
+func (wallet *Wallet) AccountsWithMeta() (accounts string) {
+	addresses := []string{}
+	_ = json.Unmarshal([]byte(wallet.Accounts()), &addresses)
+	list := []*AccountInfo{}
+	for _, addr := range addresses {
+		info := &AccountInfo{
+			Address:   addr,
+			WatchOnly: wallet.IsWatchOnly(addr),
+		}
+		if wallet.metaStore != nil {
+			info.Metadata = wallet.metaStore.Lookup(meta.ChainETH, addr)
+		}
+		list = append(list, info)
+	}
+	data, _ := json.Marshal(list)
+	return string(data)
+}
@@ -0,0 +1,396 @@
+package hd
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/tyler-smith/go-bip39"
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/text/unicode/norm"
+)
+
+// mnemonic languages
+const (
+	English            = "english"
+	ChineseSimplified  = "chinese_simplified"
+	ChineseTraditional = "chinese_traditional"
+	Japanese           = "japanese"
+	Korean             = "korean"
+	French             = "french"
+	Italian            = "italian"
+	Spanish            = "spanish"
+	Czech              = "czech"
+)
+
+// detection order, simplified and traditional Chinese share most characters
+// so when both checksums pass the earlier language wins
+var mnemonicLanguages = []string{
+	English,
+	ChineseSimplified,
+	ChineseTraditional,
+	Japanese,
+	Korean,
+	French,
+	Italian,
+	Spanish,
+	Czech,
+}
+
+type wordList struct {
+	words []string
+	index map[string]int
+}
+
+var wordLists = map[string]*wordList{}
+
+func init() {
+	for lang, words := range map[string][]string{
+		English:            wordlists.English,
+		ChineseSimplified:  wordlists.ChineseSimplified,
+		ChineseTraditional: wordlists.ChineseTraditional,
+		Japanese:           wordlists.Japanese,
+		Korean:             wordlists.Korean,
+		French:             wordlists.French,
+		Italian:            wordlists.Italian,
+		Spanish:            wordlists.Spanish,
+		Czech:              wordlists.Czech,
+	} {
+		wl := &wordList{
+			words: words,
+			index: make(map[string]int, len(words)),
+		}
+		for i, w := range words {
+			wl.index[norm.NFKD.String(w)] = i
+		}
+		wordLists[lang] = wl
+	}
+}
+
+//SupportedLanguages JSON list of mnemonic languages
+func SupportedLanguages() string {
+	data, _ := json.Marshal(mnemonicLanguages)
+	return string(data)
+}
+
+//NewMnemonicWithLanguage bits:128-256, language:english,chinese_simplified,chinese_traditional,japanese,korean,french,italian,spanish,czech
+func NewMnemonicWithLanguage(bits int, language string) (mnemonic string, err error) {
+	wl, ok := wordLists[strings.ToLower(language)]
+	if !ok {
+		return "", fmt.Errorf("language not support: %s", language)
+	}
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	words := entropyToWords(entropy, wl)
+	if strings.ToLower(language) == Japanese {
+		// BIP39 japanese mnemonics are joined with ideographic spaces
+		return strings.Join(words, "　"), nil
+	}
+	return strings.Join(words, " "), nil
+}
+
+//MnemonicLanguage detects the language of a valid mnemonic
+func MnemonicLanguage(mnemonic string) (language string, err error) {
+	words := splitMnemonic(mnemonic)
+	for _, lang := range mnemonicLanguages {
+		if _, ok := checkMnemonic(words, wordLists[lang]); ok {
+			return lang, nil
+		}
+	}
+	return "", errors.New("mnemonic is invalid")
+}
+
+//IsMnemonicValid in any supported language
+func IsMnemonicValid(mnemonic string) bool {
+	_, err := MnemonicLanguage(mnemonic)
+	return err == nil
+}
+
+// mnemonicSeed is the BIP39 seed of the words as validated: NFKD normalized,
+// lower case and joined by single spaces, so japanese ideographic spaces,
+// capitals and stray whitespace give the same seed. The passphrase is only
+// NFKD normalized.
+func mnemonicSeed(mnemonic string, passphrase string) (seed []byte, err error) {
+	if !IsMnemonicValid(mnemonic) {
+		return nil, errors.New("mnemonic is invalid")
+	}
+	return bip39.NewSeed(strings.Join(splitMnemonic(mnemonic), " "), norm.NFKD.String(passphrase)), nil
+}
+
+func splitMnemonic(mnemonic string) []string {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return words
+}
+
+func validWordCount(n int) bool {
+	return n >= 12 && n <= 24 && n%3 == 0
+}
+
+// checkMnemonic returns the word indexes, and whether all words are in the
+// list, the word count is valid and the checksum matches.
+func checkMnemonic(words []string, wl *wordList) (indexes []int, ok bool) {
+	indexes = make([]int, len(words))
+	for i, w := range words {
+		idx, found := wl.index[w]
+		if !found {
+			return nil, false
+		}
+		indexes[i] = idx
+	}
+	if !validWordCount(len(words)) {
+		return indexes, false
+	}
+	return indexes, checksumValid(indexes)
+}
+
+// checksumValid splits the 11 bit word indexes into entropy and checksum.
+func checksumValid(indexes []int) bool {
+	totalBits := len(indexes) * 11
+	csBits := totalBits / 33
+	entBits := totalBits - csBits
+	buf := make([]byte, (totalBits+7)/8)
+	for i, idx := range indexes {
+		for b := 0; b < 11; b++ {
+			if idx&(1<<uint(10-b)) != 0 {
+				pos := i*11 + b
+				buf[pos/8] |= 1 << uint(7-pos%8)
+			}
+		}
+	}
+	entropy := buf[:entBits/8]
+	hash := sha256.Sum256(entropy)
+	want := hash[0] >> uint(8-csBits)
+	got := byte(0)
+	for b := 0; b < csBits; b++ {
+		pos := entBits + b
+		got <<= 1
+		if buf[pos/8]&(1<<uint(7-pos%8)) != 0 {
+			got |= 1
+		}
+	}
+	return got == want
+}
+
+func entropyToWords(entropy []byte, wl *wordList) []string {
+	entBits := len(entropy) * 8
+	csBits := entBits / 32
+	hash := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), hash[0])
+	words := make([]string, (entBits+csBits)/11)
+	for i := range words {
+		idx := 0
+		for b := 0; b < 11; b++ {
+			pos := i*11 + b
+			idx <<= 1
+			if data[pos/8]&(1<<uint(7-pos%8)) != 0 {
+				idx |= 1
+			}
+		}
+		words[i] = wl.words[idx]
+	}
+	return words
+}
+
+// mnemonic problems reported by ValidateMnemonic
+const (
+	MnemonicUnknownWords = "unknown_words"
+	MnemonicWordCount    = "word_count"
+	MnemonicChecksum     = "checksum"
+)
+
+const maxWordSuggestions = 3
+
+//MnemonicReport ...
+type MnemonicReport struct {
+	Valid          bool          `json:"valid"`
+	Error          string        `json:"error,omitempty"`
+	Language       string        `json:"language"`
+	WordCount      int           `json:"word_count"`
+	WordCountValid bool          `json:"word_count_valid"`
+	ChecksumValid  bool          `json:"checksum_valid"`
+	UnknownWords   []UnknownWord `json:"unknown_words"`
+}
+
+//UnknownWord word not in the wordlist, index is 0 based. Suggestions are the
+//closest words by spelling, for chinese only the same word in the other script
+type UnknownWord struct {
+	Index       int      `json:"index"`
+	Word        string   `json:"word"`
+	Suggestions []string `json:"suggestions"`
+}
+
+//ValidateMnemonic returns a MnemonicReport JSON explaining what is wrong with mnemonic
+func ValidateMnemonic(mnemonic string) (reportJSON string) {
+	data, _ := json.Marshal(validateMnemonic(mnemonic))
+	return string(data)
+}
+
+func validateMnemonic(mnemonic string) *MnemonicReport {
+	words := splitMnemonic(mnemonic)
+	report := &MnemonicReport{
+		Language:       guessLanguage(words),
+		WordCount:      len(words),
+		WordCountValid: validWordCount(len(words)),
+		UnknownWords:   []UnknownWord{},
+	}
+	if lang, err := MnemonicLanguage(mnemonic); err == nil {
+		report.Language = lang
+		report.Valid = true
+		report.ChecksumValid = true
+		return report
+	}
+
+	wl := wordLists[report.Language]
+	for i, w := range words {
+		if _, ok := wl.index[w]; !ok {
+			report.UnknownWords = append(report.UnknownWords, UnknownWord{
+				Index:       i,
+				Word:        w,
+				Suggestions: suggestWord(w, report.Language),
+			})
+		}
+	}
+	switch {
+	case len(report.UnknownWords) > 0:
+		report.Error = MnemonicUnknownWords
+	case !report.WordCountValid:
+		report.Error = MnemonicWordCount
+	default:
+		report.Error = MnemonicChecksum
+	}
+	return report
+}
+
+//SuggestWords JSON list of up to limit words starting with prefix, language defaults to english.
+//A prefix typed without accents also matches accented words.
+func SuggestWords(prefix string, language string, limit int) (wordsJSON string) {
+	if language == "" {
+		language = English
+	}
+	out := []string{}
+	if wl, ok := wordLists[strings.ToLower(language)]; ok {
+		prefix = strings.ToLower(norm.NFKD.String(strings.TrimSpace(prefix)))
+		fold := prefix == stripMarks(prefix)
+		for _, w := range wl.words {
+			if limit > 0 && len(out) >= limit {
+				break
+			}
+			word := norm.NFKD.String(w)
+			if fold {
+				word = stripMarks(word)
+			}
+			if strings.HasPrefix(word, prefix) {
+				out = append(out, w)
+			}
+		}
+	}
+	data, _ := json.Marshal(out)
+	return string(data)
+}
+
+// stripMarks drops the combining marks of an NFKD string, the accents.
+func stripMarks(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// guessLanguage picks the language that knows most of the words.
+func guessLanguage(words []string) string {
+	best, bestCount := English, 0
+	for _, lang := range mnemonicLanguages {
+		count := 0
+		for _, w := range words {
+			if _, ok := wordLists[lang].index[w]; ok {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = lang, count
+		}
+	}
+	return best
+}
+
+// the simplified and traditional chinese lists share their order
+var chineseCounterpart = map[string]string{
+	ChineseSimplified:  ChineseTraditional,
+	ChineseTraditional: ChineseSimplified,
+}
+
+// suggestWord offers replacements for a word not in the list of language.
+// Chinese words are single characters, so only a character from the other
+// chinese script is mapped to its counterpart, other typos get none.
+func suggestWord(word string, language string) []string {
+	wl := wordLists[language]
+	if other, ok := chineseCounterpart[language]; ok {
+		if idx, found := wordLists[other].index[word]; found {
+			return []string{wl.words[idx]}
+		}
+		return []string{}
+	}
+	return closestWords(word, wl, maxWordSuggestions)
+}
+
+// closestWords ranks the wordlist by edit distance to word. Words made of
+// a single character (Chinese) are all one edit apart, so none are offered.
+func closestWords(word string, wl *wordList, limit int) []string {
+	out := []string{}
+	if len([]rune(word)) < 2 {
+		return out
+	}
+	for dist := 1; dist <= 2 && len(out) < limit; dist++ {
+		for _, w := range wl.words {
+			if editDistance(word, norm.NFKD.String(w)) == dist {
+				out = append(out, w)
+				if len(out) >= limit {
+					break
+				}
+			}
+		}
+	}
+	return out
+}
+
+// editDistance is the Levenshtein distance in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
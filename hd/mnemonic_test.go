@@ -0,0 +1,248 @@
+package hd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// BIP39 vectors: english from trezor/python-mnemonic with passphrase "TREZOR",
+// japanese from bip32JP (ideographic spaces, passphrase needing NFKD). There
+// are no published chinese vectors, those are the trezor entropies in the
+// chinese lists, seeds by the standard PBKDF2.
+var mnemonicVectors = []struct {
+	language   string
+	mnemonic   string
+	passphrase string
+	seed       string
+}{
+	{
+		language:   English,
+		mnemonic:   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		passphrase: "TREZOR",
+		seed:       "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		language:   English,
+		mnemonic:   "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		passphrase: "TREZOR",
+		seed:       "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		language:   English,
+		mnemonic:   "letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		passphrase: "TREZOR",
+		seed:       "d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+	},
+	{
+		language:   English,
+		mnemonic:   "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		passphrase: "TREZOR",
+		seed:       "ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+	{
+		language:   Japanese,
+		mnemonic:   "あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あおぞら",
+		passphrase: "㍍ガバヴァぱばぐゞちぢ十人十色",
+		seed:       "a262d6fb6122ecf45be09c50492b31f92e9beb7d9a845987a02cefda57a15f9c467a17872029a9e92299b5cbdf306e3a0ee620245cbd508959b6cb7ca637bd55",
+	},
+	{
+		language:   ChineseSimplified,
+		mnemonic:   "枪 疫 霉 尝 俩 闹 饿 贤 枪 疫 霉 卿",
+		passphrase: "TREZOR",
+		seed:       "816a69d6866891b246b4d33f54d6d2be624470141754396205d039bdd8003949fec4340253dde4c8e11437a181ad992f56d5b976eb9fbe48f4c5e5fec60a27e1",
+	},
+	{
+		language:   ChineseTraditional,
+		mnemonic:   "槍 疫 黴 嘗 倆 鬧 餓 賢 槍 疫 黴 卿",
+		passphrase: "TREZOR",
+		seed:       "f38af46f6bc3222b0f5aa14dd5b8b506e51131510f2450ec9fb52c28617cfa59d436055fe542e25dfa01415639d2171e41796f169f8bbc18516941dfdee8fb72",
+	},
+}
+
+func TestMnemonicVectors(t *testing.T) {
+	for _, v := range mnemonicVectors {
+		lang, err := MnemonicLanguage(v.mnemonic)
+		if err != nil {
+			t.Fatalf("%s: %s", v.mnemonic, err)
+		}
+		if lang != v.language {
+			t.Fatalf("%s: language %s, want %s", v.mnemonic, lang, v.language)
+		}
+		seed, err := SeedFromMnemonicWithPassphrase(v.mnemonic, v.passphrase)
+		if err != nil {
+			t.Fatalf("%s: %s", v.mnemonic, err)
+		}
+		if hex.EncodeToString(seed) != v.seed {
+			t.Fatalf("%s: seed %x, want %s", v.mnemonic, seed, v.seed)
+		}
+	}
+}
+
+func TestMnemonicNormalization(t *testing.T) {
+	want, err := SeedFromMnemonicWithPassphrase("legal winner thank year wave sausage worth useful legal winner thank yellow", "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []string{
+		"Legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"LEGAL WINNER THANK YEAR WAVE SAUSAGE WORTH USEFUL LEGAL WINNER THANK YELLOW",
+		"  legal winner  thank year\twave sausage worth useful legal winner thank yellow\n",
+	} {
+		seed, err := SeedFromMnemonicWithPassphrase(m, "TREZOR")
+		if err != nil {
+			t.Fatalf("%q: %s", m, err)
+		}
+		if !bytes.Equal(seed, want) {
+			t.Fatalf("%q: seed %x, want %x", m, seed, want)
+		}
+	}
+
+	// japanese with plain spaces is the same sentence
+	jp := mnemonicVectors[4]
+	seed, err := SeedFromMnemonicWithPassphrase("あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あおぞら", jp.passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(seed) != jp.seed {
+		t.Fatalf("japanese with spaces: seed %x, want %s", seed, jp.seed)
+	}
+}
+
+func TestMnemonicLanguage(t *testing.T) {
+	for _, c := range []struct {
+		mnemonic string
+		language string
+	}{
+		// both chinese lists have these words, simplified is detected first
+		{"的 的 的 的 的 的 的 的 的 的 的 在", ChineseSimplified},
+		{"壤 對 據 人 三 談 我 表 壤 對 據 不", ChineseTraditional},
+		{"abaco abaco abaco abaco abaco abaco abaco abaco abaco abaco abaco abete", Italian},
+		{"ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco abierto", Spanish},
+	} {
+		lang, err := MnemonicLanguage(c.mnemonic)
+		if err != nil {
+			t.Fatalf("%s: %s", c.mnemonic, err)
+		}
+		if lang != c.language {
+			t.Fatalf("%s: language %s, want %s", c.mnemonic, lang, c.language)
+		}
+	}
+	if _, err := MnemonicLanguage("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"); err == nil {
+		t.Fatal("bad checksum accepted")
+	}
+	for _, lang := range mnemonicLanguages {
+		m, err := NewMnemonicWithLanguage(128, lang)
+		if err != nil {
+			t.Fatalf("%s: %s", lang, err)
+		}
+		if !IsMnemonicValid(m) {
+			t.Fatalf("%s: generated mnemonic is invalid: %s", lang, m)
+		}
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	for _, c := range []struct {
+		mnemonic string
+		report   string
+	}{
+		{
+			"abandon abandn abandon abandon abandon abandon abandon abandon abandon abandon abandon abuot",
+			`{"valid":false,"error":"unknown_words","language":"english","word_count":12,"word_count_valid":true,"checksum_valid":false,"unknown_words":[{"index":1,"word":"abandn","suggestions":["abandon"]},{"index":11,"word":"abuot","suggestions":["about","abuse","adult"]}]}`,
+		},
+		{
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			`{"valid":false,"error":"word_count","language":"english","word_count":11,"word_count_valid":false,"checksum_valid":false,"unknown_words":[]}`,
+		},
+		{
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			`{"valid":false,"error":"checksum","language":"english","word_count":12,"word_count_valid":true,"checksum_valid":false,"unknown_words":[]}`,
+		},
+		{
+			"Abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			`{"valid":true,"language":"english","word_count":12,"word_count_valid":true,"checksum_valid":true,"unknown_words":[]}`,
+		},
+		{
+			"abaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco abierto",
+			`{"valid":false,"error":"unknown_words","language":"spanish","word_count":12,"word_count_valid":true,"checksum_valid":false,"unknown_words":[{"index":0,"word":"abaco","suggestions":["ábaco","tabaco","abono"]}]}`,
+		},
+		{
+			// a traditional character in a simplified mnemonic
+			"壤 对 据 人 三 谈 我 表 壤 对 據 不",
+			`{"valid":false,"error":"unknown_words","language":"chinese_simplified","word_count":12,"word_count_valid":true,"checksum_valid":false,"unknown_words":[{"index":10,"word":"據","suggestions":["据"]}]}`,
+		},
+		{
+			// no spelling suggestions for single characters
+			"壤 对 据 人 三 谈 我 表 壤 对 蘋 不",
+			`{"valid":false,"error":"unknown_words","language":"chinese_simplified","word_count":12,"word_count_valid":true,"checksum_valid":false,"unknown_words":[{"index":10,"word":"蘋","suggestions":[]}]}`,
+		},
+	} {
+		// the spanish list is stored decomposed
+		if report := ValidateMnemonic(c.mnemonic); norm.NFC.String(report) != norm.NFC.String(c.report) {
+			t.Fatalf("%s:\n got %s\nwant %s", c.mnemonic, report, c.report)
+		}
+	}
+}
+
+func TestSuggestWords(t *testing.T) {
+	for _, c := range []struct {
+		prefix   string
+		language string
+		limit    int
+		words    string
+	}{
+		{"ab", English, 3, `["abandon","ability","able"]`},
+		{"ABA", "", 0, `["abandon"]`},
+		{"xyz", English, 3, `[]`},
+		{"aba", Spanish, 2, `["ábaco"]`},
+		{"ába", Spanish, 2, `["ábaco"]`},
+		{"a", "klingon", 3, `[]`},
+	} {
+		if words := SuggestWords(c.prefix, c.language, c.limit); norm.NFC.String(words) != norm.NFC.String(c.words) {
+			t.Fatalf("%s %s: got %s, want %s", c.prefix, c.language, words, c.words)
+		}
+	}
+}
+
+func TestPassphraseAddresses(t *testing.T) {
+	for _, c := range []struct {
+		mnemonic   string
+		passphrase string
+		address    string
+	}{
+		{
+			mnemonic:   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			passphrase: "TREZOR",
+			address:    "0x9c32f71d4db8fb9e1a58b0a80df79935e7256fa6",
+		},
+		{
+			// the passphrase only matches other wallets after NFKD
+			mnemonic:   "あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あおぞら",
+			passphrase: "㍍ガバヴァぱばぐゞちぢ十人十色",
+			address:    "0x79e0a622f994c830663338bbbce81e1b6cbb58e3",
+		},
+	} {
+		w, err := NewFromMnemonicWithPassphrase(c.mnemonic, c.passphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		address, err := w.Derive("m/44'/60'/0'/0/0", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.EqualFold(address, c.address) {
+			t.Fatalf("%s: address %s, want %s", c.passphrase, address, c.address)
+		}
+		plain, err := NewFromMnemonic(c.mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other, _ := plain.Derive("m/44'/60'/0'/0/0", true); strings.EqualFold(other, address) {
+			t.Fatalf("%s: passphrase ignored", c.passphrase)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package hd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//ErrWatchOnly returned by signing methods for watch-only accounts
+var ErrWatchOnly = errors.New("watch-only account, no private key")
+
+// checkCanSign fails with ErrWatchOnly when the wallet holds no key for
+// address and with ErrWalletLocked while locked, signing methods call it
+// before dialling the node.
+func (wallet *Wallet) checkCanSign(address common.Address) error {
+	if wallet.locked {
+		return ErrWalletLocked
+	}
+	if _, ok := wallet.imported[address]; ok {
+		return nil
+	}
+	if wallet.watch[address] || wallet.hdWallet == nil {
+		return ErrWatchOnly
+	}
+	return nil
+}
+
+//AddWatchAccount monitor an address without its key, queries work and signing fails with ErrWatchOnly.
+//Like EpiK watch addresses the list is kept in memory only, and can't be changed while locked.
+func (wallet *Wallet) AddWatchAccount(address string) (err error) {
+	if wallet.locked {
+		return ErrWalletLocked
+	}
+	if !checkAddress(address) {
+		return fmt.Errorf("invalid address: %s", address)
+	}
+	if wallet.Contains(address) {
+		return fmt.Errorf("account already in wallet: %s", address)
+	}
+	addr := common.HexToAddress(address)
+	if wallet.watch == nil {
+		wallet.watch = map[common.Address]bool{}
+	}
+	wallet.watch[addr] = true
+	wallet.watchAddrs = append(wallet.watchAddrs, addr)
+	return nil
+}
+
+//RemoveWatchAccount ...
+func (wallet *Wallet) RemoveWatchAccount(address string) (err error) {
+	if wallet.locked {
+		return ErrWalletLocked
+	}
+	addr := common.HexToAddress(address)
+	if !wallet.watch[addr] {
+		return fmt.Errorf("watch account not found: %s", address)
+	}
+	delete(wallet.watch, addr)
+	for i, a := range wallet.watchAddrs {
+		if a == addr {
+			wallet.watchAddrs = append(wallet.watchAddrs[:i], wallet.watchAddrs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+//WatchAccounts JSON list of watch-only addresses
+func (wallet *Wallet) WatchAccounts() (addrs string) {
+	addresses := []string{}
+	for _, addr := range wallet.watchAddrs {
+		addresses = append(addresses, addr.Hex())
+	}
+	data, _ := json.Marshal(&addresses)
+	return string(data)
+}
+
+//IsWatchOnly ...
+func (wallet *Wallet) IsWatchOnly(address string) bool {
+	return wallet.watch[common.HexToAddress(address)]
+}
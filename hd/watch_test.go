@@ -0,0 +1,115 @@
+package hd
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/airgap"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestWatchOnly(t *testing.T) {
+	w, err := NewFromMnemonic(testMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	own, err := w.Derive("m/44'/60'/0'/0/0", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watched := common.HexToAddress("0x008aeeda4d805471df9b2a5b0f38a0c3bcba786b").Hex()
+	if err := w.AddWatchAccount(watched); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddWatchAccount(own); err == nil {
+		t.Fatal("held account added as watch-only")
+	}
+	if err := w.AddWatchAccount("0x1234"); err == nil {
+		t.Fatal("invalid address accepted")
+	}
+
+	addrs := []string{}
+	if err := json.Unmarshal([]byte(w.Accounts()), &addrs); err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 2 || addrs[0] != own || addrs[1] != watched {
+		t.Fatalf("unexpected accounts: %v", addrs)
+	}
+	if w.WatchAccounts() != `["`+watched+`"]` || !w.IsWatchOnly(watched) || w.IsWatchOnly(own) {
+		t.Fatalf("unexpected watch accounts: %s", w.WatchAccounts())
+	}
+
+	to := common.HexToAddress(own)
+	data, err := rlp.EncodeToBytes(&unsignedTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1), ChainID: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, err := airgap.Encode(airgap.KindETHTransaction, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// no node is set, signing methods must fail before dialling it
+	for name, sign := range map[string]func() error{
+		"SignText": func() error {
+			_, err := w.SignText(watched, "epik wallet")
+			return err
+		},
+		"SignHash": func() error {
+			_, err := w.SignHash(watched, make([]byte, 32))
+			return err
+		},
+		"Export": func() error {
+			_, err := w.Export(watched)
+			return err
+		},
+		"ExportKeystore": func() error {
+			_, err := w.ExportKeystore(watched, "password")
+			return err
+		},
+		"SignTransaction": func() error {
+			_, err := w.SignTransaction(watched, envelope)
+			return err
+		},
+		"Transfer": func() error {
+			_, err := w.Transfer(watched, own, "1")
+			return err
+		},
+		"TransferToken": func() error {
+			_, err := w.TransferToken(watched, own, "USDT", "1")
+			return err
+		},
+		"UniswapExactTokenForTokens": func() error {
+			_, err := w.UniswapExactTokenForTokens(watched, "USDT", "EPK", "1", "1", "")
+			return err
+		},
+	} {
+		if err := sign(); err != ErrWatchOnly {
+			t.Fatalf("%s: expected ErrWatchOnly, got %v", name, err)
+		}
+	}
+
+	// the list stays readable while locked, like the EpiK one
+	w.Lock()
+	if !w.IsWatchOnly(watched) {
+		t.Fatal("watch account lost on Lock")
+	}
+	if err := w.RemoveWatchAccount(watched); err != ErrWalletLocked {
+		t.Fatalf("RemoveWatchAccount while locked: %v", err)
+	}
+	seed, err := SeedFromMnemonic(testMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.UnlockSeed(seed); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.RemoveWatchAccount(watched); err != nil {
+		t.Fatal(err)
+	}
+	if w.IsWatchOnly(watched) || w.WatchAccounts() != "[]" {
+		t.Fatalf("watch account not removed: %s", w.WatchAccounts())
+	}
+}
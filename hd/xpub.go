@@ -0,0 +1,93 @@
+package hd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
+)
+
+// set to derive like go-ethereum-hdwallet with the issue 179 fix enabled
+const issue179FixEnvar = "GO_ETHEREUM_HDWALLET_FIX_ISSUE_179"
+
+//ExportXpub extended public key of an account level path, e.g. m/44'/60'/0'
+func (wallet *Wallet) ExportXpub(path string) (xpub string, err error) {
+	if wallet.masterKey == nil {
+		return "", ErrWatchOnly
+	}
+	p, err := hdwallet.ParseDerivationPath(path)
+	if err != nil {
+		return
+	}
+	key, err := deriveExtendedKey(wallet.masterKey, p)
+	if err != nil {
+		return
+	}
+	pub, err := key.Neuter()
+	if err != nil {
+		return
+	}
+	return pub.String(), nil
+}
+
+//NewFromXpub watch-only wallet, Derive paths are relative to the xpub: m/0/0 is the first receive address.
+//Balances and history work, signing fails with ErrWatchOnly.
+func NewFromXpub(xpub string) (wallet *Wallet, err error) {
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return nil, err
+	}
+	if key.IsPrivate() {
+		return nil, fmt.Errorf("not an extended public key")
+	}
+	return &Wallet{xpub: key}, nil
+}
+
+// deriveXpub derives a non-hardened path from the wallet xpub and watches the address.
+func (wallet *Wallet) deriveXpub(path string, pin bool) (address string, err error) {
+	p, err := hdwallet.ParseDerivationPath(path)
+	if err != nil {
+		return
+	}
+	key, err := deriveExtendedKey(wallet.xpub, p)
+	if err != nil {
+		return
+	}
+	pub, err := key.ECPubKey()
+	if err != nil {
+		return
+	}
+	address = crypto.PubkeyToAddress(*pub.ToECDSA()).Hex()
+	if pin && !wallet.IsWatchOnly(address) {
+		if err = wallet.AddWatchAccount(address); err != nil {
+			return "", err
+		}
+	}
+	return address, nil
+}
+
+// deriveExtendedKey follows go-ethereum-hdwallet, which keeps the non-standard
+// derivation by default so that existing addresses don't change.
+func deriveExtendedKey(key *hdkeychain.ExtendedKey, path accounts.DerivationPath) (*hdkeychain.ExtendedKey, error) {
+	fix := len(os.Getenv(issue179FixEnvar)) > 0
+	var err error
+	for _, n := range path {
+		if fix && key.IsAffectedByIssue172() {
+			key, err = key.Derive(n)
+		} else {
+			key, err = key.DeriveNonStandard(n)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+func newMasterKey(seed []byte) (*hdkeychain.ExtendedKey, error) {
+	return hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+}
@@ -0,0 +1,31 @@
+package hd
+
+import "testing"
+
+func TestXpub(t *testing.T) {
+	w, err := NewFromMnemonic(testMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub, err := w.ExportXpub("m/44'/60'/0'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xpub != "xpub6DCoCpSuQZB2jawqnGMEPS63ePKWkwWPH4TU45Q7LPXWuNd8TMtVxRrgjtEshuqpK3mdhaWHPFsBngh5GFZaM6si3yZdUsT8ddYM3PwnATt" {
+		t.Fatalf("unexpected xpub: %s", xpub)
+	}
+	watch, err := NewFromXpub(xpub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := watch.Derive("m/0/0", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != "0x9858EfFD232B4033E47d90003D41EC34EcaEda94" {
+		t.Fatalf("unexpected address: %s", addr)
+	}
+	if _, err := watch.SignText(addr, "epik wallet"); err != ErrWatchOnly {
+		t.Fatalf("expected ErrWatchOnly, got %v", err)
+	}
+}
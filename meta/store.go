@@ -0,0 +1,205 @@
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// chains used as keys, the same tags as the multi wallet accounts
+const (
+	ChainETH  = "eth"
+	ChainEpiK = "epik"
+)
+
+//Metadata user data attached to an address
+type Metadata struct {
+	Chain     string `json:"chain"`
+	Address   string `json:"address"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	Path      string `json:"path"`
+	Notes     string `json:"notes"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+//Store metadata keyed by chain and address, persisted as one JSON file
+type Store struct {
+	file    string
+	entries map[string]*Metadata
+	lk      sync.Mutex
+}
+
+//NewStore loads file, an empty file name keeps the store in memory only
+func NewStore(file string) (s *Store, err error) {
+	s = &Store{
+		file:    file,
+		entries: map[string]*Metadata{},
+	}
+	if file == "" {
+		return s, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	list := []*Metadata{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("decoding metadata: %w", err)
+	}
+	for _, m := range list {
+		s.entries[key(m.Chain, m.Address)] = m
+	}
+	return s, nil
+}
+
+//Set creates or updates the metadata of an address, chain:eth,epik. metadataJSON holds the Metadata
+//fields to change, fields left out keep their value.
+func (s *Store) Set(chain string, address string, metadataJSON string) (err error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	// merged under the lock so that concurrent Sets of different fields all stick
+	m := &Metadata{}
+	if old, ok := s.entries[key(chain, address)]; ok {
+		*m = *old
+	}
+	if strings.TrimSpace(metadataJSON) != "" {
+		if err := json.Unmarshal([]byte(metadataJSON), m); err != nil {
+			return err
+		}
+	}
+	return s.put(chain, address, m)
+}
+
+//Put Set for Go callers
+func (s *Store) Put(chain string, address string, m *Metadata) error {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	return s.put(chain, address, m)
+}
+
+func (s *Store) put(chain string, address string, m *Metadata) error {
+	if chain == "" || address == "" {
+		return fmt.Errorf("chain and address are required")
+	}
+	k := key(chain, address)
+	now := time.Now().Unix()
+	m.Chain = strings.ToLower(chain)
+	m.Address = address
+	m.CreatedAt = now
+	if old, ok := s.entries[k]; ok {
+		m.CreatedAt = old.CreatedAt
+	}
+	m.UpdatedAt = now
+	s.entries[k] = m
+	return s.save()
+}
+
+//Get metadata JSON of an address
+func (s *Store) Get(chain string, address string) (metadataJSON string, err error) {
+	m := s.Lookup(chain, address)
+	if m == nil {
+		return "", fmt.Errorf("metadata not found: %s %s", chain, address)
+	}
+	data, _ := json.Marshal(m)
+	return string(data), nil
+}
+
+//Lookup metadata of an address, nil when there is none
+func (s *Store) Lookup(chain string, address string) *Metadata {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	m, ok := s.entries[key(chain, address)]
+	if !ok {
+		return nil
+	}
+	c := *m
+	return &c
+}
+
+//Delete ...
+func (s *Store) Delete(chain string, address string) (err error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	k := key(chain, address)
+	if _, ok := s.entries[k]; !ok {
+		return fmt.Errorf("metadata not found: %s %s", chain, address)
+	}
+	delete(s.entries, k)
+	return s.save()
+}
+
+//List JSON list of Metadata of chain, all chains when chain is empty
+func (s *Store) List(chain string) (listJSON string) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	list := []*Metadata{}
+	for _, m := range s.sorted() {
+		if chain == "" || m.Chain == strings.ToLower(chain) {
+			list = append(list, m)
+		}
+	}
+	data, _ := json.Marshal(list)
+	return string(data)
+}
+
+func (s *Store) sorted() []*Metadata {
+	list := make([]*Metadata, 0, len(s.entries))
+	for _, m := range s.entries {
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Chain != list[j].Chain {
+			return list[i].Chain < list[j].Chain
+		}
+		return list[i].Address < list[j].Address
+	})
+	return list
+}
+
+// save writes to a temp file first so a crash never leaves a truncated file behind
+func (s *Store) save() error {
+	if s.file == "" {
+		return nil
+	}
+	data, err := json.Marshal(s.sorted())
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.file), 0700); err != nil {
+		return err
+	}
+	tmp := s.file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.file); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// ethereum addresses are case insensitive, the case only carries a checksum
+func key(chain string, address string) string {
+	chain = strings.ToLower(chain)
+	if chain == ChainETH {
+		address = strings.ToLower(address)
+	}
+	return chain + ":" + address
+}
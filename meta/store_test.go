@@ -0,0 +1,91 @@
+package meta
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "epik-meta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "meta.json")
+
+	s, err := NewStore(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+	if err := s.Set(ChainETH, addr, `{"name":"savings","path":"m/44'/60'/0'/0/0"}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set(ChainETH, addr, `{"color":"#ff0000"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	// reload, addresses of eth are case insensitive
+	s, err = NewStore(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := s.Lookup(ChainETH, "0x9858effd232b4033e47d90003d41ec34ecaeda94")
+	if m == nil || m.Name != "savings" || m.Color != "#ff0000" || m.Path != "m/44'/60'/0'/0/0" {
+		t.Fatalf("unexpected metadata: %+v", m)
+	}
+	if s.Lookup(ChainEpiK, addr) != nil {
+		t.Fatal("metadata shared between chains")
+	}
+
+	if err := s.Delete(ChainETH, addr); err != nil {
+		t.Fatal(err)
+	}
+	if list := s.List(""); list != "[]" {
+		t.Fatalf("unexpected list: %s", list)
+	}
+}
+
+func TestStoreConcurrentSet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "epik-meta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := NewStore(filepath.Join(dir, "meta.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []string{
+		`{"name":"savings"}`,
+		`{"color":"#ff0000"}`,
+		`{"path":"m/44'/60'/0'/0/0"}`,
+		`{"notes":"cold storage"}`,
+	}
+	// every field of an address is set at once, none may be lost
+	for i := 0; i < 20; i++ {
+		addr := fmt.Sprintf("f0%d", 1000+i)
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for _, f := range fields {
+			wg.Add(1)
+			go func(f string) {
+				defer wg.Done()
+				<-start
+				if err := s.Set(ChainEpiK, addr, f); err != nil {
+					t.Error(err)
+				}
+			}(f)
+		}
+		close(start)
+		wg.Wait()
+		m := s.Lookup(ChainEpiK, addr)
+		if m == nil || m.Name != "savings" || m.Color != "#ff0000" || m.Path != "m/44'/60'/0'/0/0" || m.Notes != "cold storage" {
+			t.Fatalf("concurrent updates lost: %+v", m)
+		}
+	}
+}
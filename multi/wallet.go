@@ -0,0 +1,390 @@
+package multi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/EpiK-Protocol/go-epik/chain/types"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/epik"
+	"github.com/EpiK-Protocol/epik-wallet-golib/epik/wallet"
+	"github.com/EpiK-Protocol/epik-wallet-golib/hd"
+	"github.com/EpiK-Protocol/epik-wallet-golib/meta"
+)
+
+// account chains
+const (
+	ChainETH  = meta.ChainETH
+	ChainEpiK = meta.ChainEpiK
+)
+
+// paths derived by the constructors, the same the app used before
+const (
+	DefaultETHPath  = "m/44'/60'/0'/0/0"
+	DefaultEpiKPath = "m/44'/196'/1'/0/0"
+)
+
+// names of the secrets in the keystore of NewWalletWithKeystore wallets
+const (
+	ksMnemonic = "mnemonic"
+	ksSeed     = "seed"
+
+	ktMnemonic types.KeyType = "bip39-mnemonic"
+	ktSeed     types.KeyType = "bip39-seed"
+)
+
+//Wallet one mnemonic for ETH and EpiK accounts
+type Wallet struct {
+	// both are wiped by Lock, keystore is nil for wallets kept in memory
+	mnemonic string
+	seed     []byte
+	keystore *wallet.FsKeyStore
+
+	hdWallet   *hd.Wallet
+	epikWallet *epik.Wallet
+
+	// accounts derived through this wallet, in derivation order
+	accounts  []*Account
+	metaStore *meta.Store
+	lk        sync.Mutex
+}
+
+//Account Path, Type and Scheme are empty for imported keys and watch-only addresses
+type Account struct {
+	Chain   string `json:"chain"`
+	Address string `json:"address"`
+	Path    string `json:"path"`
+	Type    string `json:"type,omitempty"`
+	Scheme  string `json:"scheme,omitempty"`
+
+	WatchOnly bool           `json:"watch_only,omitempty"`
+	Metadata  *meta.Metadata `json:"metadata,omitempty"`
+}
+
+//NewWallet new 12 word english mnemonic
+func NewWallet() (w *Wallet, err error) {
+	mnemonic, err := hd.NewMnemonic(128)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromMnemonic(mnemonic, "")
+}
+
+//NewFromMnemonic mnemonic in any BIP39 language, passphrase may be empty.
+//Derives the default ETH account and EpiK bls account.
+func NewFromMnemonic(mnemonic string, passphrase string) (w *Wallet, err error) {
+	seed, err := hd.SeedFromMnemonicWithPassphrase(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	epikWallet, err := epik.NewWallet()
+	if err != nil {
+		return nil, err
+	}
+	return newWallet(mnemonic, seed, epikWallet)
+}
+
+//NewWalletWithKeystore new 12 word english mnemonic, see NewFromMnemonicWithKeystore
+func NewWalletWithKeystore(dir string, passphrase string) (w *Wallet, err error) {
+	mnemonic, err := hd.NewMnemonic(128)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromMnemonicWithKeystore(mnemonic, "", dir, passphrase)
+}
+
+//NewFromMnemonicWithKeystore like NewFromMnemonic, the mnemonic, seed and EpiK keys are
+//encrypted with keystorePassphrase and kept in dir, reopen it with OpenKeystore.
+//Lock wipes them from memory.
+func NewFromMnemonicWithKeystore(mnemonic string, passphrase string, dir string, keystorePassphrase string) (w *Wallet, err error) {
+	seed, err := hd.SeedFromMnemonicWithPassphrase(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	ks, err := wallet.NewFsKeyStore(filepath.Join(dir, "seed"), keystorePassphrase)
+	if err != nil {
+		return nil, err
+	}
+	if err := ks.Put(ksMnemonic, types.KeyInfo{Type: ktMnemonic, PrivateKey: []byte(mnemonic)}); err != nil {
+		return nil, err
+	}
+	if err := ks.Put(ksSeed, types.KeyInfo{Type: ktSeed, PrivateKey: seed}); err != nil {
+		return nil, err
+	}
+	return openWallet(ks, mnemonic, seed, dir, keystorePassphrase)
+}
+
+//OpenKeystore wallet made by NewWalletWithKeystore or NewFromMnemonicWithKeystore
+func OpenKeystore(dir string, passphrase string) (w *Wallet, err error) {
+	ks, err := wallet.NewFsKeyStore(filepath.Join(dir, "seed"), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	mnemonic, seed, err := readSecrets(ks)
+	if err != nil {
+		return nil, err
+	}
+	return openWallet(ks, mnemonic, seed, dir, passphrase)
+}
+
+func openWallet(ks *wallet.FsKeyStore, mnemonic string, seed []byte, dir string, passphrase string) (w *Wallet, err error) {
+	epikWallet, err := epik.NewWalletWithKeystore(filepath.Join(dir, "epik"), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	w, err = newWallet(mnemonic, seed, epikWallet)
+	if err != nil {
+		return nil, err
+	}
+	w.keystore = ks
+	return w, nil
+}
+
+func readSecrets(ks *wallet.FsKeyStore) (mnemonic string, seed []byte, err error) {
+	ki, err := ks.Get(ksMnemonic)
+	if err != nil {
+		return "", nil, err
+	}
+	mnemonic = string(ki.PrivateKey)
+	ki, err = ks.Get(ksSeed)
+	if err != nil {
+		return "", nil, err
+	}
+	return mnemonic, ki.PrivateKey, nil
+}
+
+func newWallet(mnemonic string, seed []byte, epikWallet *epik.Wallet) (w *Wallet, err error) {
+	w = &Wallet{
+		mnemonic:   mnemonic,
+		seed:       seed,
+		epikWallet: epikWallet,
+	}
+	w.hdWallet, err = hd.NewFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.DeriveETH(DefaultETHPath); err != nil {
+		return nil, err
+	}
+	if _, err = w.DeriveEpiK("bls", DefaultEpiKPath, epik.KeySchemeLegacy); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+//Mnemonic for backup, empty while locked
+func (w *Wallet) Mnemonic() string {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	return w.mnemonic
+}
+
+//Lock wipes the mnemonic, seed and private keys of both chains from memory,
+//deriving and signing fail until Unlock. Only keystore wallets can be locked.
+func (w *Wallet) Lock() (err error) {
+	if w.keystore == nil {
+		return epik.ErrNotEncrypted
+	}
+	w.lk.Lock()
+	for i := range w.seed {
+		w.seed[i] = 0
+	}
+	w.seed = nil
+	w.mnemonic = ""
+	w.lk.Unlock()
+
+	w.hdWallet.Lock()
+	w.keystore.Lock()
+	return w.epikWallet.Lock()
+}
+
+//Unlock reads the seed back from the keystore
+func (w *Wallet) Unlock(passphrase string) (err error) {
+	if w.keystore == nil {
+		return epik.ErrNotEncrypted
+	}
+	if err := w.keystore.Unlock(passphrase); err != nil {
+		return err
+	}
+	mnemonic, seed, err := readSecrets(w.keystore)
+	if err != nil {
+		return err
+	}
+	if err := w.hdWallet.UnlockSeed(seed); err != nil {
+		return err
+	}
+	if err := w.epikWallet.Unlock(passphrase); err != nil {
+		return err
+	}
+	w.lk.Lock()
+	w.mnemonic = mnemonic
+	w.seed = seed
+	w.lk.Unlock()
+	return nil
+}
+
+//IsLocked ...
+func (w *Wallet) IsLocked() bool {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	return w.keystore != nil && w.seed == nil
+}
+
+//ETH the Ethereum wallet holding the derived ETH accounts
+func (w *Wallet) ETH() *hd.Wallet {
+	return w.hdWallet
+}
+
+//EpiK the EpiK wallet holding the derived EpiK accounts
+func (w *Wallet) EpiK() *epik.Wallet {
+	return w.epikWallet
+}
+
+//SetMetaStore metadata included in Accounts, derivation paths are recorded in it
+func (w *Wallet) SetMetaStore(store *meta.Store) (err error) {
+	w.lk.Lock()
+	w.metaStore = store
+	accounts := append([]*Account{}, w.accounts...)
+	w.lk.Unlock()
+
+	w.hdWallet.SetMetaStore(store)
+	w.epikWallet.SetMetaStore(store)
+	for _, acc := range accounts {
+		if err := w.recordPath(acc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//DeriveETH ...
+func (w *Wallet) DeriveETH(path string) (address string, err error) {
+	address, err = w.hdWallet.Derive(path, true)
+	if err != nil {
+		return "", err
+	}
+	acc := &Account{Chain: ChainETH, Address: address, Path: path}
+	w.addAccount(acc)
+	if err := w.recordPath(acc); err != nil {
+		return "", err
+	}
+	return address, nil
+}
+
+//DeriveEpiK t:bls,secp256k1 scheme:legacy,bip32,eip2333
+func (w *Wallet) DeriveEpiK(t string, path string, scheme string) (address string, err error) {
+	if scheme == "" {
+		scheme = epik.KeySchemeLegacy
+	}
+	w.lk.Lock()
+	seed := w.seed
+	w.lk.Unlock()
+	if seed == nil {
+		return "", epik.ErrWalletLocked
+	}
+	address, err = w.epikWallet.GenerateKeyWithScheme(t, seed, path, scheme)
+	if err != nil {
+		return "", err
+	}
+	acc := &Account{
+		Chain:   ChainEpiK,
+		Address: address,
+		Path:    path,
+		Type:    strings.ToLower(t),
+		Scheme:  scheme,
+	}
+	w.addAccount(acc)
+	if err := w.recordPath(acc); err != nil {
+		return "", err
+	}
+	return address, nil
+}
+
+//Derive chain:eth,epik, t and scheme are ignored for eth
+func (w *Wallet) Derive(chain string, t string, path string, scheme string) (address string, err error) {
+	switch strings.ToLower(chain) {
+	case ChainETH:
+		return w.DeriveETH(path)
+	case ChainEpiK:
+		return w.DeriveEpiK(t, path, scheme)
+	default:
+		return "", fmt.Errorf("chain not support: %s", chain)
+	}
+}
+
+//Accounts JSON list of Account, derived accounts first then keys imported into the chain wallets
+func (w *Wallet) Accounts() (accountsJSON string, err error) {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	out := append([]*Account{}, w.accounts...)
+	known := map[string]bool{}
+	for _, acc := range w.accounts {
+		known[acc.Chain+":"+acc.Address] = true
+	}
+
+	ethAddrs := []string{}
+	if err := json.Unmarshal([]byte(w.hdWallet.Accounts()), &ethAddrs); err != nil {
+		return "", err
+	}
+	for _, addr := range ethAddrs {
+		if !known[ChainETH+":"+addr] {
+			out = append(out, &Account{Chain: ChainETH, Address: addr, WatchOnly: w.hdWallet.IsWatchOnly(addr)})
+		}
+	}
+	epikAddrs, err := w.epikWallet.AddrList()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range epikAddrs {
+		if !known[ChainEpiK+":"+addr] {
+			out = append(out, &Account{Chain: ChainEpiK, Address: addr, WatchOnly: w.epikWallet.IsWatchOnly(addr)})
+		}
+	}
+
+	if w.metaStore != nil {
+		for i, acc := range out {
+			c := *acc
+			c.Metadata = w.metaStore.Lookup(acc.Chain, acc.Address)
+			out[i] = &c
+		}
+	}
+
+	data, _ := json.Marshal(out)
+	return string(data), nil
+}
+
+func (w *Wallet) addAccount(acc *Account) {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	for _, a := range w.accounts {
+		if a.Chain == acc.Chain && a.Address == acc.Address {
+			return
+		}
+	}
+	w.accounts = append(w.accounts, acc)
+}
+
+// recordPath saves the derivation path of acc unless the metadata already has one
+func (w *Wallet) recordPath(acc *Account) error {
+	w.lk.Lock()
+	store := w.metaStore
+	w.lk.Unlock()
+
+	if store == nil {
+		return nil
+	}
+	m := store.Lookup(acc.Chain, acc.Address)
+	if m == nil {
+		m = &meta.Metadata{}
+	}
+	if m.Path != "" {
+		return nil
+	}
+	m.Path = acc.Path
+	return store.Put(acc.Chain, acc.Address, m)
+}
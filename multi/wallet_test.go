@@ -0,0 +1,144 @@
+package multi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/EpiK-Protocol/epik-wallet-golib/epik"
+	"github.com/EpiK-Protocol/epik-wallet-golib/hd"
+	"github.com/EpiK-Protocol/epik-wallet-golib/meta"
+)
+
+func TestAccounts(t *testing.T) {
+	w, err := NewFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Derive(ChainEpiK, "secp256k1", "m/44'/461'/0'/0/0", "bip32"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Derive("btc", "", "m/44'/0'/0'/0/0", ""); err == nil {
+		t.Fatal("unknown chain accepted")
+	}
+
+	data, err := w.Accounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := []*Account{}
+	if err := json.Unmarshal([]byte(data), &accounts); err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 3 {
+		t.Fatalf("unexpected accounts: %s", data)
+	}
+	// BIP44 test vector for the abandon mnemonic
+	if accounts[0].Chain != ChainETH || accounts[0].Address != "0x9858EfFD232B4033E47d90003D41EC34EcaEda94" {
+		t.Fatalf("unexpected eth account: %+v", accounts[0])
+	}
+	if accounts[1].Chain != ChainEpiK || accounts[1].Path != DefaultEpiKPath || accounts[1].Type != "bls" {
+		t.Fatalf("unexpected epik account: %+v", accounts[1])
+	}
+	if accounts[2].Scheme != "bip32" || !w.EpiK().HasAddr(accounts[2].Address) {
+		t.Fatalf("unexpected epik account: %+v", accounts[2])
+	}
+}
+
+func TestAccountsMetadata(t *testing.T) {
+	w, err := NewFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := meta.NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetMetaStore(store); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(ChainETH, "0x9858effd232b4033e47d90003d41ec34ecaeda94", `{"name":"savings"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := w.Accounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := []*Account{}
+	if err := json.Unmarshal([]byte(data), &accounts); err != nil {
+		t.Fatal(err)
+	}
+	m := accounts[0].Metadata
+	if m == nil || m.Name != "savings" || m.Path != DefaultETHPath {
+		t.Fatalf("unexpected metadata: %+v", m)
+	}
+	if m := accounts[1].Metadata; m == nil || m.Path != DefaultEpiKPath {
+		t.Fatalf("unexpected metadata: %+v", m)
+	}
+}
+
+func TestLock(t *testing.T) {
+	const mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	dir, err := ioutil.TempDir("", "multi-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	w, err := NewFromMnemonicWithKeystore(mnemonic, "", dir, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := w.Accounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethAddr := "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+	if err := w.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if !w.IsLocked() || w.Mnemonic() != "" || w.seed != nil {
+		t.Fatal("secrets kept after Lock")
+	}
+	if _, err := w.ETH().SignText(ethAddr, "epik wallet"); !errors.Is(err, hd.ErrWalletLocked) {
+		t.Fatalf("SignText: %v", err)
+	}
+	if _, err := w.DeriveEpiK("bls", "m/44'/196'/1'/0/1", ""); !errors.Is(err, epik.ErrWalletLocked) {
+		t.Fatalf("DeriveEpiK: %v", err)
+	}
+	if locked, err := w.Accounts(); err != nil || locked != before {
+		t.Fatalf("accounts changed while locked: %s %v", locked, err)
+	}
+
+	if err := w.Unlock("wrong"); err == nil {
+		t.Fatal("wrong passphrase accepted")
+	}
+	if err := w.Unlock("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if w.Mnemonic() != mnemonic {
+		t.Fatal("mnemonic not restored")
+	}
+	if _, err := w.ETH().SignText(ethAddr, "epik wallet"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenKeystore(dir, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := reopened.Accounts(); after != before {
+		t.Fatalf("unexpected accounts after reopening: %s", after)
+	}
+
+	plain, err := NewFromMnemonic(mnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.Lock(); err != epik.ErrNotEncrypted {
+		t.Fatalf("Lock of a memory wallet: %v", err)
+	}
+}
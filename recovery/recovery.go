@@ -0,0 +1,45 @@
+package recovery
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PathIndex is replaced by the account index in path templates
+const PathIndex = "{index}"
+
+// DefaultGapLimit BIP44 address gap limit
+const DefaultGapLimit = 20
+
+//RecoveredAccount ...
+type RecoveredAccount struct {
+	Path    string `json:"path"`
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+	Nonce   uint64 `json:"nonce"`
+}
+
+//Walk looks up the indexes of pathTemplate in order until gapLimit consecutive accounts are unused
+//(DefaultGapLimit when gapLimit <= 0) and returns the used ones. A template without PathIndex is a single account.
+func Walk(pathTemplate string, gapLimit int, lookup func(path string) (account *RecoveredAccount, used bool, err error)) ([]*RecoveredAccount, error) {
+	if gapLimit <= 0 {
+		gapLimit = DefaultGapLimit
+	}
+	recovered := []*RecoveredAccount{}
+	for index, gap := 0, 0; gap < gapLimit; index++ {
+		account, used, err := lookup(strings.ReplaceAll(pathTemplate, PathIndex, strconv.Itoa(index)))
+		if err != nil {
+			return nil, err
+		}
+		if used {
+			gap = 0
+			recovered = append(recovered, account)
+		} else {
+			gap++
+		}
+		if !strings.Contains(pathTemplate, PathIndex) {
+			break
+		}
+	}
+	return recovered, nil
+}
@@ -0,0 +1,62 @@
+package recovery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	// indexes 0, 2 and 6 are used
+	used := map[string]bool{"m/0": true, "m/2": true, "m/6": true}
+	lookups := []string{}
+	lookup := func(path string) (*RecoveredAccount, bool, error) {
+		lookups = append(lookups, path)
+		return &RecoveredAccount{Path: path}, used[path], nil
+	}
+
+	for _, c := range []struct {
+		gapLimit int
+		found    string
+		lookups  int
+	}{
+		{gapLimit: 1, found: "m/0", lookups: 2},
+		{gapLimit: 2, found: "m/0 m/2", lookups: 5},
+		{gapLimit: 4, found: "m/0 m/2 m/6", lookups: 11},
+		{gapLimit: 0, found: "m/0 m/2 m/6", lookups: 7 + DefaultGapLimit},
+	} {
+		lookups = lookups[:0]
+		accounts, err := Walk("m/"+PathIndex, c.gapLimit, lookup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths := []string{}
+		for _, a := range accounts {
+			paths = append(paths, a.Path)
+		}
+		if strings.Join(paths, " ") != c.found || len(lookups) != c.lookups {
+			t.Fatalf("gap limit %d: found %v after %d lookups", c.gapLimit, paths, len(lookups))
+		}
+	}
+
+	// a fixed path is looked up once
+	lookups = lookups[:0]
+	accounts, err := Walk("m/2", 5, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || len(lookups) != 1 {
+		t.Fatalf("fixed path: %d accounts after %v", len(accounts), lookups)
+	}
+
+	failed := errors.New("node down")
+	_, err = Walk("m/"+PathIndex, 5, func(path string) (*RecoveredAccount, bool, error) {
+		if path == "m/3" {
+			return nil, false, failed
+		}
+		return lookup(path)
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("lookup error lost: %v", err)
+	}
+}